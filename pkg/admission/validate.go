@@ -0,0 +1,99 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Validator checks a single decoded object and returns a non-nil error
+// describing why it should be rejected. Kept narrow (one method per kind)
+// rather than a single `Validate(runtime.Object)` so the controller can
+// implement only the kinds it resolves informers for, without a type
+// switch duplicated on both sides of the package boundary.
+type Validator interface {
+	// ValidateKongIngress rejects a KongIngress whose raw JSON spec
+	// wouldn't translate to valid Route/Service/Upstream overrides.
+	ValidateKongIngress(raw []byte) error
+	// ValidateKongPlugin rejects a KongPlugin with no pluginName or a
+	// config Kong itself would refuse.
+	ValidateKongPlugin(raw []byte) error
+	// ValidateIngress rejects an Ingress referencing a Service that
+	// doesn't exist, the same check syncIngress performs at sync time,
+	// moved up to admission time so it surfaces immediately.
+	ValidateIngress(ing *v1beta1.Ingress) error
+	// ValidateKongRoute rejects a KongRoute with an invalid hostname/SNI in
+	// its Hosts, the same check syncRoute performs at sync time, moved up to
+	// admission time so it surfaces immediately instead of only showing up
+	// in the KongRoute's status after it's already been persisted.
+	ValidateKongRoute(raw []byte) error
+}
+
+// Server serves a single /validate endpoint handling AdmissionReview
+// requests for KongIngress, KongPlugin, KongRoute, and Ingress, delegating the actual
+// checks to a Validator.
+type Server struct {
+	validator Validator
+}
+
+// NewServer builds a Server backed by validator.
+func NewServer(validator Validator) *Server {
+	return &Server{validator: validator}
+}
+
+// Handler returns the http.Handler to mount at the webhook's /validate path.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(s.serveValidate)
+}
+
+func (s *Server) serveValidate(w http.ResponseWriter, r *http.Request) {
+	var review admissionv1beta1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("admission: failed decoding review: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	response := &admissionv1beta1.AdmissionResponse{
+		UID:     review.Request.UID,
+		Allowed: true,
+	}
+	if err := s.validate(review.Request); err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{Message: err.Error()}
+		glog.Warningf("admission: rejected %s %s/%s: %s",
+			review.Request.Kind.Kind, review.Request.Namespace, review.Request.Name, err)
+	}
+
+	review.Response = response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		glog.Errorf("admission: failed writing review response: %s", err)
+	}
+}
+
+func (s *Server) validate(req *admissionv1beta1.AdmissionRequest) error {
+	switch req.Kind.Kind {
+	case "KongIngress":
+		return s.validator.ValidateKongIngress(req.Object.Raw)
+	case "KongPlugin":
+		return s.validator.ValidateKongPlugin(req.Object.Raw)
+	case "KongRoute":
+		return s.validator.ValidateKongRoute(req.Object.Raw)
+	case "Ingress":
+		ing := &v1beta1.Ingress{}
+		if err := json.Unmarshal(req.Object.Raw, ing); err != nil {
+			return fmt.Errorf("failed decoding ingress: %s", err)
+		}
+		return s.validator.ValidateIngress(ing)
+	default:
+		// Object kinds this webhook isn't registered for are allowed
+		// through unchecked rather than rejected, so a ValidatingWebhook-
+		// Configuration scoped wider than expected fails open.
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package admission
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// CABundleUpdater patches a single ValidatingWebhookConfiguration's
+// webhooks[].clientConfig.caBundle. Kept as an interface, the same way
+// licenseagent.Injector decouples the agent from pkg/controller/pkg/kong,
+// so this package never needs a Kubernetes clientset import of its own.
+type CABundleUpdater interface {
+	UpdateCABundle(caBundle []byte) error
+}
+
+// WebhookConfigReconciler keeps a ValidatingWebhookConfiguration's caBundle
+// in sync with the webhook's own serving CA, so operators don't hand-copy
+// a PEM blob into the configuration and watch it go stale on cert rotation.
+// Runs leader-elected since every replica of the controller would otherwise
+// race to write the same object.
+type WebhookConfigReconciler struct {
+	caSource     func() ([]byte, error)
+	updater      CABundleUpdater
+	lock         resourcelock.Interface
+	resyncPeriod time.Duration
+}
+
+// NewWebhookConfigReconciler builds a WebhookConfigReconciler that calls
+// caSource (resolving the webhook's own CA certificate, PEM-encoded,
+// whether it's backed by a file or a Secret) and pushes the result through
+// updater every resyncPeriod while leading. lock backs the leader election
+// so only one controller replica performs the write at a time.
+func NewWebhookConfigReconciler(caSource func() ([]byte, error), updater CABundleUpdater, lock resourcelock.Interface, resyncPeriod time.Duration) *WebhookConfigReconciler {
+	if resyncPeriod <= 0 {
+		resyncPeriod = time.Minute
+	}
+	return &WebhookConfigReconciler{caSource: caSource, updater: updater, lock: lock, resyncPeriod: resyncPeriod}
+}
+
+// Run blocks, contesting leadership via r.lock, and reconciling the
+// caBundle on an interval for as long as this replica holds it. It returns
+// when stopc is closed.
+func (r *WebhookConfigReconciler) Run(stopc <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopc
+		cancel()
+	}()
+
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:          r.lock,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				glog.Infof("admission: acquired webhook-config leadership, reconciling caBundle every %s", r.resyncPeriod)
+				r.reconcile()
+				ticker := time.NewTicker(r.resyncPeriod)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						r.reconcile()
+					case <-ctx.Done():
+						return
+					}
+				}
+			},
+			OnStoppedLeading: func() {
+				glog.Infof("admission: lost webhook-config leadership")
+			},
+		},
+	})
+}
+
+// reconcile re-resolves the CA bundle via r.caSource and pushes it through
+// r.updater, logging (rather than crashing the reconcile loop) on failure
+// since a transient read/API error should be retried on the next tick, not
+// take down the whole webhook-config reconciler.
+func (r *WebhookConfigReconciler) reconcile() {
+	caBundle, err := r.caSource()
+	if err != nil {
+		glog.Errorf("admission: failed resolving webhook CA bundle: %s", err)
+		return
+	}
+	if err := r.updater.UpdateCABundle(caBundle); err != nil {
+		glog.Errorf("admission: failed updating webhook caBundle: %s", err)
+	}
+}
@@ -0,0 +1,273 @@
+// Package v1alpha1 defines the KongPlugin and KongRoute custom resources,
+// a typed alternative to stuffing Kong-specific configuration into
+// `kolihub.io/plugin-*` Ingress annotations.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KongPluginSpec describes a single Kong plugin and its configuration.
+type KongPluginSpec struct {
+	// PluginName is the Kong plugin identifier, e.g. "jwt", "cors", "key-auth".
+	PluginName string `json:"pluginName"`
+	// Config is passed through to Kong as-is; its shape is plugin-specific
+	// so it's kept untyped rather than mirrored per plugin.
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// KongPluginStatus reflects the last observed state of the plugin in Kong.
+type KongPluginStatus struct {
+	// KongID is the UUID Kong assigned the plugin, once applied.
+	KongID string `json:"kongID,omitempty"`
+	// LastAppliedError holds the last error returned by Kong, if any.
+	LastAppliedError string `json:"lastAppliedError,omitempty"`
+}
+
+// KongPlugin is a namespaced, named Kong plugin definition that a KongRoute
+// (or, via `kolihub.io/plugin-refs`, an Ingress) can reference instead of
+// embedding a JSON blob in an annotation.
+type KongPlugin struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KongPluginSpec   `json:"spec"`
+	Status KongPluginStatus `json:"status,omitempty"`
+}
+
+// KongPluginList is a list of KongPlugin resources.
+type KongPluginList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KongPlugin `json:"items"`
+}
+
+// KongRouteSpec describes a Kong API/route independently of any Ingress
+// object: hosts, paths, methods and the Service it forwards to, plus the
+// KongPlugins that should be attached to it.
+type KongRouteSpec struct {
+	Hosts        []string `json:"hosts,omitempty"`
+	Paths        []string `json:"paths,omitempty"`
+	Methods      []string `json:"methods,omitempty"`
+	StripURI     bool     `json:"stripUri,omitempty"`
+	PreserveHost bool     `json:"preserveHost,omitempty"`
+
+	// ServiceName/ServicePort select the Kubernetes Service this route's
+	// upstream_url is derived from, the same way Ingress.Spec.Backend does.
+	ServiceName string `json:"serviceName"`
+	ServicePort int32  `json:"servicePort"`
+
+	// PluginRefs names KongPlugin objects, in this route's namespace, that
+	// should be attached to the resulting Kong API.
+	PluginRefs []string `json:"pluginRefs,omitempty"`
+}
+
+// KongRouteStatus reflects the Kong entity this route translates to, so
+// `kubectl get kongroute` shows drift without tailing controller logs.
+type KongRouteStatus struct {
+	KongID           string `json:"kongID,omitempty"`
+	LastAppliedError string `json:"lastAppliedError,omitempty"`
+}
+
+// KongRoute is a namespaced, first-class alternative to expressing routing
+// through Ingress + annotations.
+type KongRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KongRouteSpec   `json:"spec"`
+	Status KongRouteStatus `json:"status,omitempty"`
+}
+
+// KongRouteList is a list of KongRoute resources.
+type KongRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KongRoute `json:"items"`
+}
+
+// KongIngressRoute overrides Kong Route fields an Ingress would otherwise
+// leave at Kong's defaults, mirroring the subset of go-kong's Route fields
+// the operator can actually apply.
+type KongIngressRoute struct {
+	Methods      []string `json:"methods,omitempty"`
+	StripPath    *bool    `json:"stripPath,omitempty"`
+	PreserveHost *bool    `json:"preserveHost,omitempty"`
+	Protocols    []string `json:"protocols,omitempty"`
+}
+
+// KongIngressService overrides Kong Service fields.
+type KongIngressService struct {
+	Retries        *int `json:"retries,omitempty"`
+	ConnectTimeout *int `json:"connectTimeout,omitempty"`
+	WriteTimeout   *int `json:"writeTimeout,omitempty"`
+	ReadTimeout    *int `json:"readTimeout,omitempty"`
+}
+
+// KongIngressHealthThreshold mirrors go-kong's healthcheck threshold fields.
+type KongIngressHealthThreshold struct {
+	HTTPFailures int `json:"httpFailures,omitempty"`
+	TCPFailures  int `json:"tcpFailures,omitempty"`
+	Successes    int `json:"successes,omitempty"`
+}
+
+// KongIngressActiveHealthcheck mirrors go-kong's Upstream.Healthchecks.Active.
+type KongIngressActiveHealthcheck struct {
+	HTTPPath  string                      `json:"httpPath,omitempty"`
+	Timeout   int                         `json:"timeout,omitempty"`
+	Unhealthy *KongIngressHealthThreshold `json:"unhealthy,omitempty"`
+}
+
+// KongIngressPassiveHealthcheck mirrors go-kong's Upstream.Healthchecks.Passive.
+type KongIngressPassiveHealthcheck struct {
+	Unhealthy *KongIngressHealthThreshold `json:"unhealthy,omitempty"`
+}
+
+// KongIngressHealthcheck mirrors go-kong's Upstream.Healthchecks, limited to
+// the active/passive fields the operator applies.
+type KongIngressHealthcheck struct {
+	Active  *KongIngressActiveHealthcheck  `json:"active,omitempty"`
+	Passive *KongIngressPassiveHealthcheck `json:"passive,omitempty"`
+}
+
+// KongIngressUpstream overrides Kong Upstream fields, including active/
+// passive healthcheck behavior.
+type KongIngressUpstream struct {
+	HashOn       string                  `json:"hashOn,omitempty"`
+	HashOnHeader string                  `json:"hashOnHeader,omitempty"`
+	Healthchecks *KongIngressHealthcheck `json:"healthchecks,omitempty"`
+}
+
+// KongIngressSpec carries Ingress-level overrides for Kong Route/Service/
+// Upstream fields that can't otherwise be expressed through annotations.
+// Route, Service, and Upstream are the preferred, fine-grained way to set
+// these; the flat fields below them exist for backward compatibility with
+// configuration authored against a single proxy struct, and are only
+// consulted when the corresponding subtype field is unset (see the
+// Resolved* accessors).
+type KongIngressSpec struct {
+	Route    *KongIngressRoute    `json:"route,omitempty"`
+	Service  *KongIngressService  `json:"service,omitempty"`
+	Upstream *KongIngressUpstream `json:"upstream,omitempty"`
+
+	StripPath      *bool  `json:"stripPath,omitempty"`
+	PreserveHost   *bool  `json:"preserveHost,omitempty"`
+	Retries        *int   `json:"retries,omitempty"`
+	ConnectTimeout *int   `json:"connectTimeout,omitempty"`
+	WriteTimeout   *int   `json:"writeTimeout,omitempty"`
+	ReadTimeout    *int   `json:"readTimeout,omitempty"`
+	HashOn         string `json:"hashOn,omitempty"`
+	HashOnHeader   string `json:"hashOnHeader,omitempty"`
+}
+
+// ResolvedStripPath returns Route.StripPath if set, falling back to the
+// legacy flat StripPath field, then to def.
+func (s *KongIngressSpec) ResolvedStripPath(def bool) bool {
+	if s.Route != nil && s.Route.StripPath != nil {
+		return *s.Route.StripPath
+	}
+	if s.StripPath != nil {
+		return *s.StripPath
+	}
+	return def
+}
+
+// ResolvedPreserveHost returns Route.PreserveHost if set, falling back to
+// the legacy flat PreserveHost field, then to def.
+func (s *KongIngressSpec) ResolvedPreserveHost(def bool) bool {
+	if s.Route != nil && s.Route.PreserveHost != nil {
+		return *s.Route.PreserveHost
+	}
+	if s.PreserveHost != nil {
+		return *s.PreserveHost
+	}
+	return def
+}
+
+// ResolvedRetries returns Service.Retries if set, falling back to the
+// legacy flat Retries field, then to def.
+func (s *KongIngressSpec) ResolvedRetries(def int) int {
+	if s.Service != nil && s.Service.Retries != nil {
+		return *s.Service.Retries
+	}
+	if s.Retries != nil {
+		return *s.Retries
+	}
+	return def
+}
+
+// ResolvedConnectTimeout returns Service.ConnectTimeout if set, falling back
+// to the legacy flat ConnectTimeout field, then to def.
+func (s *KongIngressSpec) ResolvedConnectTimeout(def int) int {
+	if s.Service != nil && s.Service.ConnectTimeout != nil {
+		return *s.Service.ConnectTimeout
+	}
+	if s.ConnectTimeout != nil {
+		return *s.ConnectTimeout
+	}
+	return def
+}
+
+// ResolvedWriteTimeout returns Service.WriteTimeout if set, falling back to
+// the legacy flat WriteTimeout field, then to def.
+func (s *KongIngressSpec) ResolvedWriteTimeout(def int) int {
+	if s.Service != nil && s.Service.WriteTimeout != nil {
+		return *s.Service.WriteTimeout
+	}
+	if s.WriteTimeout != nil {
+		return *s.WriteTimeout
+	}
+	return def
+}
+
+// ResolvedReadTimeout returns Service.ReadTimeout if set, falling back to
+// the legacy flat ReadTimeout field, then to def.
+func (s *KongIngressSpec) ResolvedReadTimeout(def int) int {
+	if s.Service != nil && s.Service.ReadTimeout != nil {
+		return *s.Service.ReadTimeout
+	}
+	if s.ReadTimeout != nil {
+		return *s.ReadTimeout
+	}
+	return def
+}
+
+// ResolvedHashOn returns Upstream.HashOn if set, falling back to the legacy
+// flat HashOn field, then to def.
+func (s *KongIngressSpec) ResolvedHashOn(def string) string {
+	if s.Upstream != nil && s.Upstream.HashOn != "" {
+		return s.Upstream.HashOn
+	}
+	if s.HashOn != "" {
+		return s.HashOn
+	}
+	return def
+}
+
+// KongIngressStatus reflects the last error (if any) encountered while
+// applying this KongIngress's overrides.
+type KongIngressStatus struct {
+	LastAppliedError string `json:"lastAppliedError,omitempty"`
+}
+
+// KongIngress carries fine-grained Kong Route/Service/Upstream overrides for
+// the Ingress(es) that reference it via the `kolihub.io/kong-ingress`
+// annotation (mirroring how `kubernetes.io/ingress.class` selects an
+// Ingress controller).
+type KongIngress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KongIngressSpec   `json:"spec"`
+	Status KongIngressStatus `json:"status,omitempty"`
+}
+
+// KongIngressList is a list of KongIngress resources.
+type KongIngressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KongIngress `json:"items"`
+}
@@ -0,0 +1,102 @@
+package v1alpha1
+
+import "testing"
+
+func intPtr(i int) *int    { return &i }
+func boolPtr(b bool) *bool { return &b }
+
+func TestResolvedStripPath(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedStripPath(true); got != true {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	if got := (&KongIngressSpec{StripPath: boolPtr(false)}).ResolvedStripPath(true); got != false {
+		t.Errorf("expected the legacy flat field to win over def, got %v", got)
+	}
+	spec := &KongIngressSpec{
+		Route:     &KongIngressRoute{StripPath: boolPtr(true)},
+		StripPath: boolPtr(false),
+	}
+	if got := spec.ResolvedStripPath(false); got != true {
+		t.Errorf("expected Route.StripPath to win over the legacy flat field, got %v", got)
+	}
+}
+
+func TestResolvedPreserveHost(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedPreserveHost(false); got != false {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	spec := &KongIngressSpec{
+		Route:        &KongIngressRoute{PreserveHost: boolPtr(true)},
+		PreserveHost: boolPtr(false),
+	}
+	if got := spec.ResolvedPreserveHost(false); got != true {
+		t.Errorf("expected Route.PreserveHost to win over the legacy flat field, got %v", got)
+	}
+}
+
+func TestResolvedRetries(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedRetries(5); got != 5 {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	if got := (&KongIngressSpec{Retries: intPtr(3)}).ResolvedRetries(5); got != 3 {
+		t.Errorf("expected the legacy flat field to win over def, got %v", got)
+	}
+	spec := &KongIngressSpec{
+		Service: &KongIngressService{Retries: intPtr(1)},
+		Retries: intPtr(3),
+	}
+	if got := spec.ResolvedRetries(5); got != 1 {
+		t.Errorf("expected Service.Retries to win over the legacy flat field, got %v", got)
+	}
+}
+
+func TestResolvedConnectTimeout(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedConnectTimeout(60000); got != 60000 {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	spec := &KongIngressSpec{
+		Service:        &KongIngressService{ConnectTimeout: intPtr(1000)},
+		ConnectTimeout: intPtr(2000),
+	}
+	if got := spec.ResolvedConnectTimeout(60000); got != 1000 {
+		t.Errorf("expected Service.ConnectTimeout to win over the legacy flat field, got %v", got)
+	}
+}
+
+func TestResolvedWriteTimeout(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedWriteTimeout(60000); got != 60000 {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	spec := &KongIngressSpec{
+		Service:      &KongIngressService{WriteTimeout: intPtr(1000)},
+		WriteTimeout: intPtr(2000),
+	}
+	if got := spec.ResolvedWriteTimeout(60000); got != 1000 {
+		t.Errorf("expected Service.WriteTimeout to win over the legacy flat field, got %v", got)
+	}
+}
+
+func TestResolvedReadTimeout(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedReadTimeout(60000); got != 60000 {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	if got := (&KongIngressSpec{ReadTimeout: intPtr(3000)}).ResolvedReadTimeout(60000); got != 3000 {
+		t.Errorf("expected the legacy flat field to win over def, got %v", got)
+	}
+}
+
+func TestResolvedHashOn(t *testing.T) {
+	if got := (&KongIngressSpec{}).ResolvedHashOn("none"); got != "none" {
+		t.Errorf("expected def to win with nothing set, got %v", got)
+	}
+	if got := (&KongIngressSpec{HashOn: "ip"}).ResolvedHashOn("none"); got != "ip" {
+		t.Errorf("expected the legacy flat field to win over def, got %v", got)
+	}
+	spec := &KongIngressSpec{
+		Upstream: &KongIngressUpstream{HashOn: "header"},
+		HashOn:   "ip",
+	}
+	if got := spec.ResolvedHashOn("none"); got != "header" {
+		t.Errorf("expected Upstream.HashOn to win over the legacy flat field, got %v", got)
+	}
+}
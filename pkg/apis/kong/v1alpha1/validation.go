@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// hostnamePattern matches a DNS hostname or a Kong wildcard SNI with the
+// wildcard confined to a single leading or trailing label (e.g.
+// "*.example.com" or "example.*"), the same shape Kong itself accepts for a
+// Route's hosts/SNIs.
+var hostnamePattern = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*(\.\*)?$`)
+
+// ValidateHostname rejects hostnames/SNIs Kong would reject outright, so the
+// error surfaces at admission time instead of as an opaque Kong API error
+// once a Route is already being synced.
+func ValidateHostname(host string) error {
+	if host == "" {
+		return fmt.Errorf("hostname must not be empty")
+	}
+	if len(host) > 253 {
+		return fmt.Errorf("hostname %q exceeds 253 characters", host)
+	}
+	if !hostnamePattern.MatchString(host) {
+		return fmt.Errorf("hostname %q is not a valid hostname or wildcard SNI", host)
+	}
+	return nil
+}
+
+// Validate rejects a KongRoute whose Hosts contains an invalid hostname/SNI.
+func (spec *KongRouteSpec) Validate() error {
+	for _, host := range spec.Hosts {
+		if err := ValidateHostname(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
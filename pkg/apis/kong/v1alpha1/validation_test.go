@@ -0,0 +1,47 @@
+package v1alpha1
+
+import "testing"
+
+func TestValidateHostname(t *testing.T) {
+	cases := []struct {
+		host    string
+		wantErr bool
+	}{
+		{host: "example.com"},
+		{host: "api.example.com"},
+		{host: "*.example.com"},
+		{host: "example.*"},
+		{host: ""},
+		{host: "-example.com", wantErr: true},
+		{host: "example..com", wantErr: true},
+		{host: "example.com/", wantErr: true},
+	}
+	for _, c := range cases {
+		err := ValidateHostname(c.host)
+		if (err != nil) != (c.wantErr || c.host == "") {
+			t.Errorf("ValidateHostname(%q) error = %v, wantErr %v", c.host, err, c.wantErr || c.host == "")
+		}
+	}
+}
+
+func TestValidateHostnameRejectsOverlongHostname(t *testing.T) {
+	long := ""
+	for len(long) < 254 {
+		long += "a"
+	}
+	if err := ValidateHostname(long); err == nil {
+		t.Errorf("expected a 254+ character hostname to be rejected")
+	}
+}
+
+func TestKongRouteSpecValidate(t *testing.T) {
+	valid := &KongRouteSpec{Hosts: []string{"example.com", "*.example.com"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid hosts to pass, got: %s", err)
+	}
+
+	invalid := &KongRouteSpec{Hosts: []string{"example.com", "bad..host"}}
+	if err := invalid.Validate(); err == nil {
+		t.Errorf("expected an invalid host among otherwise-valid ones to fail")
+	}
+}
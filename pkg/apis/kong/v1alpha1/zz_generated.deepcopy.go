@@ -0,0 +1,429 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongPluginSpec) DeepCopyInto(out *KongPluginSpec) {
+	*out = *in
+	if in.Config != nil {
+		out.Config = make(map[string]interface{}, len(in.Config))
+		for k, v := range in.Config {
+			out.Config[k] = v
+		}
+	}
+}
+
+// DeepCopy creates a new KongPluginSpec by deep copying the receiver.
+func (in *KongPluginSpec) DeepCopy() *KongPluginSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KongPluginSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongPlugin) DeepCopyInto(out *KongPlugin) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy creates a new KongPlugin by deep copying the receiver.
+func (in *KongPlugin) DeepCopy() *KongPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(KongPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *KongPlugin) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongPluginList) DeepCopyInto(out *KongPluginList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		out.Items = make([]KongPlugin, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy creates a new KongPluginList by deep copying the receiver.
+func (in *KongPluginList) DeepCopy() *KongPluginList {
+	if in == nil {
+		return nil
+	}
+	out := new(KongPluginList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *KongPluginList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongRouteSpec) DeepCopyInto(out *KongRouteSpec) {
+	*out = *in
+	out.Hosts = append([]string{}, in.Hosts...)
+	out.Paths = append([]string{}, in.Paths...)
+	out.Methods = append([]string{}, in.Methods...)
+	out.PluginRefs = append([]string{}, in.PluginRefs...)
+}
+
+// DeepCopy creates a new KongRouteSpec by deep copying the receiver.
+func (in *KongRouteSpec) DeepCopy() *KongRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KongRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongRoute) DeepCopyInto(out *KongRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy creates a new KongRoute by deep copying the receiver.
+func (in *KongRoute) DeepCopy() *KongRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(KongRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *KongRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongRouteList) DeepCopyInto(out *KongRouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		out.Items = make([]KongRoute, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy creates a new KongRouteList by deep copying the receiver.
+func (in *KongRouteList) DeepCopy() *KongRouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(KongRouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *KongRouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressRoute) DeepCopyInto(out *KongIngressRoute) {
+	*out = *in
+	out.Methods = append([]string{}, in.Methods...)
+	out.Protocols = append([]string{}, in.Protocols...)
+	if in.StripPath != nil {
+		b := *in.StripPath
+		out.StripPath = &b
+	}
+	if in.PreserveHost != nil {
+		b := *in.PreserveHost
+		out.PreserveHost = &b
+	}
+}
+
+// DeepCopy creates a new KongIngressRoute by deep copying the receiver.
+func (in *KongIngressRoute) DeepCopy() *KongIngressRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressService) DeepCopyInto(out *KongIngressService) {
+	*out = *in
+	if in.Retries != nil {
+		v := *in.Retries
+		out.Retries = &v
+	}
+	if in.ConnectTimeout != nil {
+		v := *in.ConnectTimeout
+		out.ConnectTimeout = &v
+	}
+	if in.WriteTimeout != nil {
+		v := *in.WriteTimeout
+		out.WriteTimeout = &v
+	}
+	if in.ReadTimeout != nil {
+		v := *in.ReadTimeout
+		out.ReadTimeout = &v
+	}
+}
+
+// DeepCopy creates a new KongIngressService by deep copying the receiver.
+func (in *KongIngressService) DeepCopy() *KongIngressService {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressHealthThreshold) DeepCopyInto(out *KongIngressHealthThreshold) {
+	*out = *in
+}
+
+// DeepCopy creates a new KongIngressHealthThreshold by deep copying the receiver.
+func (in *KongIngressHealthThreshold) DeepCopy() *KongIngressHealthThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressHealthThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressActiveHealthcheck) DeepCopyInto(out *KongIngressActiveHealthcheck) {
+	*out = *in
+	if in.Unhealthy != nil {
+		out.Unhealthy = in.Unhealthy.DeepCopy()
+	}
+}
+
+// DeepCopy creates a new KongIngressActiveHealthcheck by deep copying the receiver.
+func (in *KongIngressActiveHealthcheck) DeepCopy() *KongIngressActiveHealthcheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressActiveHealthcheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressPassiveHealthcheck) DeepCopyInto(out *KongIngressPassiveHealthcheck) {
+	*out = *in
+	if in.Unhealthy != nil {
+		out.Unhealthy = in.Unhealthy.DeepCopy()
+	}
+}
+
+// DeepCopy creates a new KongIngressPassiveHealthcheck by deep copying the receiver.
+func (in *KongIngressPassiveHealthcheck) DeepCopy() *KongIngressPassiveHealthcheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressPassiveHealthcheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressHealthcheck) DeepCopyInto(out *KongIngressHealthcheck) {
+	*out = *in
+	if in.Active != nil {
+		out.Active = in.Active.DeepCopy()
+	}
+	if in.Passive != nil {
+		out.Passive = in.Passive.DeepCopy()
+	}
+}
+
+// DeepCopy creates a new KongIngressHealthcheck by deep copying the receiver.
+func (in *KongIngressHealthcheck) DeepCopy() *KongIngressHealthcheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressHealthcheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressUpstream) DeepCopyInto(out *KongIngressUpstream) {
+	*out = *in
+	if in.Healthchecks != nil {
+		out.Healthchecks = in.Healthchecks.DeepCopy()
+	}
+}
+
+// DeepCopy creates a new KongIngressUpstream by deep copying the receiver.
+func (in *KongIngressUpstream) DeepCopy() *KongIngressUpstream {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressUpstream)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressSpec) DeepCopyInto(out *KongIngressSpec) {
+	*out = *in
+	if in.Route != nil {
+		out.Route = in.Route.DeepCopy()
+	}
+	if in.Service != nil {
+		out.Service = in.Service.DeepCopy()
+	}
+	if in.Upstream != nil {
+		out.Upstream = in.Upstream.DeepCopy()
+	}
+	if in.StripPath != nil {
+		b := *in.StripPath
+		out.StripPath = &b
+	}
+	if in.PreserveHost != nil {
+		b := *in.PreserveHost
+		out.PreserveHost = &b
+	}
+	if in.Retries != nil {
+		v := *in.Retries
+		out.Retries = &v
+	}
+	if in.ConnectTimeout != nil {
+		v := *in.ConnectTimeout
+		out.ConnectTimeout = &v
+	}
+	if in.WriteTimeout != nil {
+		v := *in.WriteTimeout
+		out.WriteTimeout = &v
+	}
+	if in.ReadTimeout != nil {
+		v := *in.ReadTimeout
+		out.ReadTimeout = &v
+	}
+}
+
+// DeepCopy creates a new KongIngressSpec by deep copying the receiver.
+func (in *KongIngressSpec) DeepCopy() *KongIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngress) DeepCopyInto(out *KongIngress) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy creates a new KongIngress by deep copying the receiver.
+func (in *KongIngress) DeepCopy() *KongIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *KongIngress) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies all properties of this object into another object of
+// the same type that is provided as a pointer.
+func (in *KongIngressList) DeepCopyInto(out *KongIngressList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		out.Items = make([]KongIngress, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy creates a new KongIngressList by deep copying the receiver.
+func (in *KongIngressList) DeepCopy() *KongIngressList {
+	if in == nil {
+		return nil
+	}
+	out := new(KongIngressList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *KongIngressList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
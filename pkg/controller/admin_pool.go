@@ -0,0 +1,373 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/kong"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	adminPoolSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kong_controller_admin_pool_size",
+		Help: "Number of Kong Admin API endpoints currently discovered by the admin pool.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(adminPoolSize)
+}
+
+// adminInstance wraps the pair of clients the controller already knows how
+// to talk to a single Kong Admin API with, keyed by the endpoint address it
+// was resolved from.
+type adminInstance struct {
+	addr       string
+	kongcli    *kong.CoreClient
+	kongclient *kongswagger.APIClient
+}
+
+// AdminPool discovers the live Kong Admin API endpoints behind one or more
+// headless Kubernetes Services (or a static list of addresses) and keeps a
+// client pair warm for each of them, so the controller can fan writes out to
+// every replica instead of a single, arbitrarily-chosen pod.
+type AdminPool struct {
+	namespace   string
+	svcName     string
+	svcSelector string
+	portName    string
+	tlsConfig   restclient.TLSClientConfig
+
+	// static holds the instances for a pool built from a fixed address list
+	// (NewAdminPoolFromURLs): no informer is run and resolve is never
+	// called again after construction.
+	static bool
+
+	// quorum is the minimum number of instances ForEach requires to succeed
+	// before it reports the overall call as failed. Zero (the default) means
+	// every discovered instance must succeed, matching the pool's original
+	// single-Service behavior.
+	quorum int
+
+	infEndpoints cache.SharedIndexInformer
+
+	mu        sync.RWMutex
+	instances map[string]*adminInstance
+}
+
+// NewAdminPool builds an AdminPool that watches the Endpoints object for
+// namespace/svcName. Call Run to start the informer before using the pool.
+func NewAdminPool(client kubernetes.Interface, namespace, svcName, portName string, tlsConfig restclient.TLSClientConfig, resyncPeriod time.Duration) *AdminPool {
+	p := &AdminPool{
+		namespace: namespace,
+		svcName:   svcName,
+		portName:  portName,
+		tlsConfig: tlsConfig,
+		instances: make(map[string]*adminInstance),
+	}
+	p.infEndpoints = cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(
+			client.Core().RESTClient(), "endpoints", namespace,
+			fields.OneTermEqualSelector("metadata.name", svcName),
+		),
+		&v1.Endpoints{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	p.infEndpoints.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { p.resolve(obj.(*v1.Endpoints)) },
+		UpdateFunc: func(o, n interface{}) { p.resolve(n.(*v1.Endpoints)) },
+		DeleteFunc: func(obj interface{}) { p.resolve(&v1.Endpoints{}) },
+	})
+	return p
+}
+
+// NewAdminPoolFromSelector builds an AdminPool that watches every Endpoints
+// object in namespace matching labelSelector, merging all of their addresses
+// into a single pool. Use this (via KongAdminServiceSelector) when Kong
+// Admin APIs are fronted by more than one headless Service, e.g. one per
+// availability zone.
+func NewAdminPoolFromSelector(client kubernetes.Interface, namespace, labelSelector, portName string, tlsConfig restclient.TLSClientConfig, resyncPeriod time.Duration) *AdminPool {
+	p := &AdminPool{
+		namespace:   namespace,
+		svcSelector: labelSelector,
+		portName:    portName,
+		tlsConfig:   tlsConfig,
+		instances:   make(map[string]*adminInstance),
+	}
+	p.infEndpoints = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = labelSelector
+				return client.Core().Endpoints(namespace).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = labelSelector
+				return client.Core().Endpoints(namespace).Watch(options)
+			},
+		},
+		&v1.Endpoints{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	p.infEndpoints.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { p.resolveAll() },
+		UpdateFunc: func(o, n interface{}) { p.resolveAll() },
+		DeleteFunc: func(obj interface{}) { p.resolveAll() },
+	})
+	return p
+}
+
+// NewAdminPoolFromURLs builds a static AdminPool from a fixed, comma
+// separated list of `host:port` addresses (KongAdminURLs), for deployments
+// that front Kong with a known, rarely-changing set of admin endpoints
+// instead of Service/Endpoints discovery.
+func NewAdminPoolFromURLs(urls string, tlsConfig restclient.TLSClientConfig) (*AdminPool, error) {
+	p := &AdminPool{
+		static:    true,
+		tlsConfig: tlsConfig,
+		instances: make(map[string]*adminInstance),
+	}
+	for _, host := range strings.Split(urls, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		kongcli, err := kong.NewCoreClient(host, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("admin-pool: failed building client for endpoint %s: %s", host, err)
+		}
+		kongclient := kongswagger.NewAPIClient(kongswagger.NewConfiguration(host))
+		p.instances[host] = &adminInstance{addr: host, kongcli: kongcli, kongclient: kongclient}
+	}
+	adminPoolSize.Set(float64(len(p.instances)))
+	return p, nil
+}
+
+// SetQuorum configures the minimum number of instances that must succeed for
+// ForEach to report overall success. n <= 0 restores the default (every
+// instance must succeed).
+func (p *AdminPool) SetQuorum(n int) {
+	p.mu.Lock()
+	p.quorum = n
+	p.mu.Unlock()
+}
+
+// Run starts the underlying Endpoints informer and blocks until stopc is
+// closed. It also re-resolves the pool periodically so that clients
+// reconnect even if an informer event is missed. A static pool (built via
+// NewAdminPoolFromURLs) has nothing to watch and returns immediately.
+func (p *AdminPool) Run(reResolveInterval time.Duration, stopc <-chan struct{}) {
+	if p.static {
+		return
+	}
+	go p.infEndpoints.Run(stopc)
+	if !cache.WaitForCacheSync(stopc, p.infEndpoints.HasSynced) {
+		return
+	}
+	if reResolveInterval <= 0 {
+		reResolveInterval = 30 * time.Second
+	}
+	ticker := time.NewTicker(reResolveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if p.svcSelector != "" {
+				p.resolveAll()
+				continue
+			}
+			if obj, exists, err := p.infEndpoints.GetStore().GetByKey(p.namespace + "/" + p.svcName); err == nil && exists {
+				p.resolve(obj.(*v1.Endpoints))
+			}
+		case <-stopc:
+			return
+		}
+	}
+}
+
+// resolveAll rebuilds the pool from every Endpoints object currently in the
+// selector-mode informer's store, merging addresses across every matching
+// Service.
+func (p *AdminPool) resolveAll() {
+	live := make(map[string]bool)
+	cache.ListAll(p.infEndpoints.GetStore(), labels.Everything(), func(obj interface{}) {
+		p.mergeEndpoints(obj.(*v1.Endpoints), live)
+	})
+	p.prune(live)
+}
+
+// resolve rebuilds the pool's client set from the given Endpoints object,
+// adding clients for newly-ready addresses and dropping ones that are gone.
+func (p *AdminPool) resolve(ep *v1.Endpoints) {
+	live := make(map[string]bool)
+	p.mergeEndpoints(ep, live)
+	p.prune(live)
+}
+
+// mergeEndpoints adds a client for every ready address in ep that isn't
+// already in the pool, recording every address it saw (live or not) into
+// live so the caller can prune stale instances afterwards.
+func (p *AdminPool) mergeEndpoints(ep *v1.Endpoints, live map[string]bool) {
+	for _, subset := range ep.Subsets {
+		port := int32(0)
+		for _, prt := range subset.Ports {
+			if p.portName == "" || prt.Name == p.portName {
+				port = prt.Port
+				break
+			}
+		}
+		if port == 0 {
+			continue
+		}
+		for _, addr := range subset.Addresses {
+			host := fmt.Sprintf("%s:%d", addr.IP, port)
+			live[host] = true
+			p.mu.RLock()
+			_, ok := p.instances[host]
+			p.mu.RUnlock()
+			if ok {
+				continue
+			}
+			kongcli, err := kong.NewCoreClient(host, p.tlsConfig)
+			if err != nil {
+				glog.Errorf("admin-pool: failed building client for endpoint %s: %s", host, err)
+				continue
+			}
+			kongclient := kongswagger.NewAPIClient(kongswagger.NewConfiguration(host))
+			p.mu.Lock()
+			p.instances[host] = &adminInstance{addr: host, kongcli: kongcli, kongclient: kongclient}
+			p.mu.Unlock()
+			glog.Infof("admin-pool: added admin endpoint %s", host)
+		}
+	}
+}
+
+// prune drops every instance not present in live and publishes the
+// resulting pool size.
+func (p *AdminPool) prune(live map[string]bool) {
+	p.mu.Lock()
+	for host := range p.instances {
+		if !live[host] {
+			delete(p.instances, host)
+			glog.Infof("admin-pool: removed admin endpoint %s", host)
+		}
+	}
+	size := len(p.instances)
+	p.mu.Unlock()
+	adminPoolSize.Set(float64(size))
+}
+
+// Len returns the number of healthy admin endpoints currently in the pool.
+func (p *AdminPool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.instances)
+}
+
+// Addrs returns the address of every instance currently in the pool, for
+// callers (e.g. the declarative translator backend) that need to target a
+// single instance directly instead of fanning a write out through ForEach.
+func (p *AdminPool) Addrs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	addrs := make([]string, 0, len(p.instances))
+	for addr := range p.instances {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// WaitUntilReady blocks until at least one admin endpoint has been
+// discovered or stopc is closed, so the controller doesn't start syncing
+// against an empty pool on startup.
+func (p *AdminPool) WaitUntilReady(stopc <-chan struct{}) bool {
+	for {
+		if p.Len() > 0 {
+			return true
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-stopc:
+			return false
+		}
+	}
+}
+
+// ForEach issues fn against every client pair in the pool and aggregates any
+// failures. The call is reported as failed only if fewer than the pool's
+// quorum (see SetQuorum) succeeded; with the default quorum of 0, every
+// instance must succeed, matching the pool's original all-or-nothing
+// behavior.
+func (p *AdminPool) ForEach(fn func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error) error {
+	p.mu.RLock()
+	instances := make([]*adminInstance, 0, len(p.instances))
+	for _, inst := range p.instances {
+		instances = append(instances, inst)
+	}
+	quorum := p.quorum
+	p.mu.RUnlock()
+
+	if len(instances) == 0 {
+		return fmt.Errorf("admin-pool: no healthy admin endpoints available")
+	}
+	if quorum <= 0 {
+		quorum = len(instances)
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, inst := range instances {
+		wg.Add(1)
+		go func(inst *adminInstance) {
+			defer wg.Done()
+			if err := fn(inst.kongcli, inst.kongclient); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %s", inst.addr, err))
+				mu.Unlock()
+			}
+		}(inst)
+	}
+	wg.Wait()
+
+	successes := len(instances) - len(errs)
+	if successes < quorum {
+		return fmt.Errorf("admin-pool: only %d/%d instances succeeded, quorum is %d: %v", successes, len(instances), quorum, errs)
+	}
+	return nil
+}
+
+// Healthz reports the pool status for the controller's /healthz endpoint.
+func (p *AdminPool) Healthz() (healthy int, ok bool) {
+	healthy = p.Len()
+	return healthy, healthy > 0
+}
+
+// ServeHTTP implements a /healthz handler reporting the number of admin
+// endpoints currently in the pool, returning 503 when the pool is empty so
+// readiness/liveness probes can route traffic away from a stuck replica.
+func (p *AdminPool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	healthy, ok := p.Healthz()
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "no healthy kong admin endpoints\n")
+		return
+	}
+	fmt.Fprintf(w, "%d healthy kong admin endpoint(s)\n", healthy)
+}
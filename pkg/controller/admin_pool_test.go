@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/kong"
+)
+
+func newTestAdminPool(size int) *AdminPool {
+	p := &AdminPool{instances: make(map[string]*adminInstance, size)}
+	for i := 0; i < size; i++ {
+		addr := fmt.Sprintf("10.0.0.%d:8001", i)
+		p.instances[addr] = &adminInstance{addr: addr}
+	}
+	return p
+}
+
+// failFirstN returns a ForEach fn that fails for exactly the first n calls
+// it observes and succeeds for the rest, counted with an atomic so it stays
+// correct under ForEach's concurrent fan-out instead of racing a plain int.
+func failFirstN(n int32) func(*kong.CoreClient, *kongswagger.APIClient) error {
+	var seen int32
+	return func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+		if atomic.AddInt32(&seen, 1) <= n {
+			return fmt.Errorf("boom")
+		}
+		return nil
+	}
+}
+
+// TestAdminPoolForEachDefaultQuorumRequiresAllInstances covers the
+// zero-quorum default: matching the pool's original all-or-nothing
+// behavior, a single failing instance out of many must still fail the call.
+func TestAdminPoolForEachDefaultQuorumRequiresAllInstances(t *testing.T) {
+	p := newTestAdminPool(3)
+	err := p.ForEach(failFirstN(1))
+	if err == nil {
+		t.Fatalf("expected a single failure to fail the call under the default quorum, got nil")
+	}
+}
+
+// TestAdminPoolForEachQuorumToleratesPartialFailure covers SetQuorum:
+// with a quorum below the pool size, enough instances succeeding should
+// report overall success even though some failed.
+func TestAdminPoolForEachQuorumToleratesPartialFailure(t *testing.T) {
+	p := newTestAdminPool(3)
+	p.SetQuorum(2)
+
+	err := p.ForEach(failFirstN(1))
+	if err != nil {
+		t.Fatalf("expected 2/3 successes to satisfy quorum 2, got error: %s", err)
+	}
+}
+
+// TestAdminPoolForEachQuorumStillFailsBelowThreshold covers SetQuorum's
+// failure path: fewer successes than the configured quorum must still fail.
+func TestAdminPoolForEachQuorumStillFailsBelowThreshold(t *testing.T) {
+	p := newTestAdminPool(3)
+	p.SetQuorum(3)
+
+	err := p.ForEach(failFirstN(1))
+	if err == nil {
+		t.Fatalf("expected 2/3 successes to fail quorum 3, got nil")
+	}
+}
+
+// TestAdminPoolForEachNoInstances covers the empty-pool guard: ForEach must
+// refuse to report success when there's nothing to fan out to.
+func TestAdminPoolForEachNoInstances(t *testing.T) {
+	p := newTestAdminPool(0)
+	err := p.ForEach(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+		t.Fatalf("fn should never be called against an empty pool")
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expected an empty pool to fail ForEach, got nil")
+	}
+}
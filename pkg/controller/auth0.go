@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	auth0 "github.com/jgensler8/go-auth0/generated/client"
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/kong"
+	"gopkg.in/square/go-jose.v2/json"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	auth0LastRefresh = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kong_controller_auth0_last_refresh_timestamp_seconds",
+		Help: "Unix timestamp of the last successful Auth0 JWKS refresh, per host.",
+	}, []string{"host"})
+	auth0KeyCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kong_controller_auth0_key_count",
+		Help: "Number of active signing keys found in an Auth0 tenant's JWKS, per host.",
+	}, []string{"host"})
+)
+
+func init() {
+	prometheus.MustRegister(auth0LastRefresh, auth0KeyCount)
+}
+
+// auth0KeyTracker remembers, per Auth0 host, the first time each kid was
+// observed missing from the tenant's JWKS, so pruneStaleAuth0Kids can apply
+// a grace period instead of deleting a credential the moment a refresh
+// doesn't see it (a transient JWKS fetch problem shouldn't lock out tokens
+// signed with a key that's still valid).
+type auth0KeyTracker struct {
+	mu           sync.Mutex
+	missingSince map[string]map[string]time.Time // host -> kid -> first-missing
+}
+
+func newAuth0KeyTracker() *auth0KeyTracker {
+	return &auth0KeyTracker{missingSince: make(map[string]map[string]time.Time)}
+}
+
+// auth0JWK is the subset of a JWKS key entry the JWT plugin needs.
+type auth0JWK struct {
+	Kid string   `json:"kid"`
+	X5c []string `json:"x5c"`
+}
+
+type auth0JWKS struct {
+	Keys []auth0JWK `json:"keys"`
+}
+
+// fetchAuth0JWKS retrieves and parses a tenant's JWKS document.
+func fetchAuth0JWKS(host string) (*auth0JWKS, error) {
+	resp, err := http.Get(fmt.Sprintf("https://%s/.well-known/jwks.json", host))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching jwks for host %s", resp.StatusCode, host)
+	}
+	var out auth0JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// rsaPublicKeyPEM extracts the PEM-encoded RSA public key from the leading
+// certificate in the JWK's x5c chain.
+func (jwk auth0JWK) rsaPublicKeyPEM() (string, error) {
+	if len(jwk.X5c) == 0 {
+		return "", fmt.Errorf("jwk %s has no x5c certificate chain", jwk.Kid)
+	}
+	der, err := base64.StdEncoding.DecodeString(jwk.X5c[0])
+	if err != nil {
+		return "", err
+	}
+	return pemFromCertDER(der)
+}
+
+// pemFromCertDER parses a DER-encoded x509 certificate and PEM-encodes its
+// public key, the shape Kong's rsa_public_key jwt credential field expects.
+func pemFromCertDER(der []byte) (string, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "", err
+	}
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	buf := bytes.NewBufferString("")
+	if err := pem.Encode(buf, &pem.Block{Type: "PUBLIC KEY", Bytes: asn1Bytes}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// runAuth0Refresh periodically re-reconciles JWT credentials for every host
+// currently carrying the jwtAuth0DomainAnnotation, so a tenant rotating (or
+// adding) a signing key is picked up without anyone touching the Ingress.
+func (k *KongController) runAuth0Refresh(stopc <-chan struct{}) {
+	interval := time.Duration(k.cfg.Auth0RefreshInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			k.refreshAuth0Hosts()
+		case <-stopc:
+			return
+		}
+	}
+}
+
+// refreshAuth0Hosts re-syncs JWT credentials for every distinct Auth0 host
+// referenced by a Kong ingress currently in the cache.
+func (k *KongController) refreshAuth0Hosts() {
+	hosts := make(map[string]*v1beta1.Ingress)
+	cache.ListAll(k.infIng.GetStore(), labels.Everything(), func(obj interface{}) {
+		ing := obj.(*v1beta1.Ingress)
+		if host := ing.Annotations[jwtAuth0DomainAnnotation]; host != "" {
+			hosts[host] = ing
+		}
+	})
+	for host, ing := range hosts {
+		err := k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+			return k.reconcileAuth0ForHost(kongclient, host, ing)
+		})
+		if err != nil {
+			glog.Errorf("auth0-refresh: failed reconciling host %s: %s", host, err)
+		}
+	}
+}
+
+// reconcileAuth0ForHost ensures the default consumer exists for host and
+// syncs its JWT credentials from the tenant's JWKS, falling back to the
+// legacy single-PEM bootstrap when JWKS retrieval fails (e.g. on a tenant
+// that hasn't enabled it, or a transient outage).
+func (k *KongController) reconcileAuth0ForHost(kongclient *kongswagger.APIClient, host string, ing *v1beta1.Ingress) error {
+	if err := k.ensureAuth0Consumer(kongclient, host, ing); err != nil {
+		return err
+	}
+
+	jwks, err := fetchAuth0JWKS(host)
+	if err != nil {
+		glog.Warningf("auth0: failed fetching jwks for host %s, falling back to single-PEM bootstrap: %s", host, err)
+		return k.bootstrapAuth0LegacyPEM(kongclient, host, ing)
+	}
+	if len(jwks.Keys) == 0 {
+		err := fmt.Errorf("auth0 tenant %s returned an empty jwks", host)
+		k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "jwt-credential", host, err, ing)
+		return err
+	}
+
+	list, _, err := kongclient.DefaultApi.ListJWTCredentials(host)
+	if err != nil {
+		glog.Errorf("Failed to list JWT credentials for default consumer (%s)", host)
+		k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "jwt-credential", host, err, ing)
+		return err
+	}
+	existingByKid := make(map[string]kongswagger.JwtCredential)
+	for _, cred := range list.Data {
+		if cred.Kid != "" {
+			existingByKid[cred.Kid] = cred
+		}
+	}
+
+	seenKids := make(map[string]bool, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		seenKids[jwk.Kid] = true
+		if _, ok := existingByKid[jwk.Kid]; ok {
+			continue // already has a credential for this kid, leave it alone
+		}
+		pemKey, err := jwk.rsaPublicKeyPEM()
+		if err != nil {
+			k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "jwt-credential", host, err, ing)
+			return err
+		}
+		cred := kongswagger.JwtCredential{
+			Algorithm:    "RS256",
+			RsaPublicKey: pemKey,
+			// iss field ends with a '/'
+			Key: "https://" + host + "/",
+			Kid: jwk.Kid,
+		}
+		if _, _, err := kongclient.DefaultApi.CreateJWTCredential(host, cred); err != nil {
+			glog.Errorf("Failed to create JWT credential kid %s for host (%s)", jwk.Kid, host)
+			k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "jwt-credential", host, err, ing)
+			return err
+		}
+		k.recordApplySuccess(ReasonAuth0Configured, "jwt-credential", jwk.Kid, ing)
+		glog.Infof("auth0: added jwt credential for host %s kid %s", host, jwk.Kid)
+	}
+
+	k.pruneStaleAuth0Kids(kongclient, host, existingByKid, seenKids)
+
+	auth0LastRefresh.WithLabelValues(host).Set(float64(time.Now().Unix()))
+	auth0KeyCount.WithLabelValues(host).Set(float64(len(jwks.Keys)))
+	return nil
+}
+
+// pruneStaleAuth0Kids deletes JWT credentials for kids that have been absent
+// from host's JWKS for at least Auth0KeyGracePeriod.
+func (k *KongController) pruneStaleAuth0Kids(kongclient *kongswagger.APIClient, host string, existingByKid map[string]kongswagger.JwtCredential, seenKids map[string]bool) {
+	grace := time.Duration(k.cfg.Auth0KeyGracePeriod) * time.Second
+	if grace <= 0 {
+		grace = 24 * time.Hour
+	}
+	now := time.Now()
+
+	k.auth0Keys.mu.Lock()
+	defer k.auth0Keys.mu.Unlock()
+	missingSince, ok := k.auth0Keys.missingSince[host]
+	if !ok {
+		missingSince = make(map[string]time.Time)
+		k.auth0Keys.missingSince[host] = missingSince
+	}
+
+	for kid := range seenKids {
+		delete(missingSince, kid)
+	}
+
+	for kid, cred := range existingByKid {
+		if seenKids[kid] {
+			continue
+		}
+		first, tracked := missingSince[kid]
+		if !tracked {
+			missingSince[kid] = now
+			continue
+		}
+		if now.Sub(first) < grace {
+			continue
+		}
+		if err := kongclient.DefaultApi.DeleteJWTCredential(host, cred.Id); err != nil {
+			glog.Errorf("auth0: failed removing stale jwt credential kid %s for host %s: %s", kid, host, err)
+			continue
+		}
+		delete(missingSince, kid)
+		glog.Infof("auth0: removed stale jwt credential for host %s kid %s (missing for %s)", host, kid, now.Sub(first))
+	}
+}
+
+// ensureAuth0Consumer makes sure a Kong consumer named after host exists,
+// creating it on first sight.
+func (k *KongController) ensureAuth0Consumer(kongclient *kongswagger.APIClient, host string, ing *v1beta1.Ingress) error {
+	_, res, err := kongclient.DefaultApi.GetConsumer(host)
+	if err == nil {
+		return nil
+	}
+	if res == nil || res.StatusCode != http.StatusNotFound {
+		glog.Errorf("Failed to get consumer (%s) in Auth0 auto-configuration", host)
+		k.recordApplyFailure(ReasonKongAPIApplyFailed, "consumer", host, err, ing)
+		return err
+	}
+	consumer := kongswagger.Consumer{Username: host}
+	if _, err := kongclient.DefaultApi.CreateConsumer(consumer); err != nil {
+		glog.Errorf("Failed to create default JWT-associated Consumer for host (%s)", host)
+		k.recordApplyFailure(ReasonKongAPIApplyFailed, "consumer", host, err, ing)
+		return err
+	}
+	return nil
+}
+
+// bootstrapAuth0LegacyPEM is the pre-JWKS single-key path, kept as a
+// fallback for tenants (or networks) where the JWKS endpoint can't be
+// reached, so first-time bootstrap still works.
+func (k *KongController) bootstrapAuth0LegacyPEM(kongclient *kongswagger.APIClient, host string, ing *v1beta1.Ingress) error {
+	cfg := auth0.DefaultTransportConfig().WithHost(host)
+	client := auth0.NewHTTPClientWithConfig(nil, cfg)
+
+	certBuf := bytes.NewBufferString("")
+	if _, err := client.Operations.GetPEM(nil, certBuf); err != nil {
+		glog.Errorf("Failed to get x509 certificate from Auth0")
+		k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "auth0-host", host, err, ing)
+		return err
+	}
+	block, _ := pem.Decode(certBuf.Bytes())
+	pemKey, err := pemFromCertDER(block.Bytes)
+	if err != nil {
+		glog.Errorf("Failed to parse x509 certificate returned by Auth0")
+		k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "auth0-host", host, err, ing)
+		return err
+	}
+
+	list, _, err := kongclient.DefaultApi.ListJWTCredentials(host)
+	if err != nil {
+		glog.Errorf("Failed to list JWT credentials for default consumer (%s)", host)
+		k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "jwt-credential", host, err, ing)
+		return err
+	}
+	if list.Total > 0 {
+		return nil
+	}
+	jwtcred := kongswagger.JwtCredential{
+		Algorithm:    "RS256",
+		RsaPublicKey: pemKey,
+		Key:          "https://" + host + "/",
+	}
+	if _, _, err := kongclient.DefaultApi.CreateJWTCredential(host, jwtcred); err != nil {
+		glog.Errorf("Failed to create JWT credential for default consumer (%s)", host)
+		k.recordApplyFailure(ReasonAuth0DiscoveryFailed, "jwt-credential", host, err, ing)
+		return err
+	}
+	k.recordApplySuccess(ReasonAuth0Configured, "jwt-credential", host, ing)
+	return nil
+}
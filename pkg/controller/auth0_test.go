@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+)
+
+func newTestAuth0Controller(gracePeriodSeconds int64) *KongController {
+	return &KongController{
+		cfg:       &Config{Auth0KeyGracePeriod: gracePeriodSeconds},
+		auth0Keys: newAuth0KeyTracker(),
+	}
+}
+
+// TestPruneStaleAuth0KidsTracksFirstMissing covers the grace-period
+// bookkeeping's first branch: a kid missing for the first time is recorded
+// with its first-missing time but not yet deleted, since the grace period
+// hasn't elapsed. Passing a nil kongclient here would panic if the deletion
+// path were (wrongly) reached, which is itself part of what this asserts.
+func TestPruneStaleAuth0KidsTracksFirstMissing(t *testing.T) {
+	k := newTestAuth0Controller(3600) // 1h grace, plenty of room
+	existing := map[string]kongswagger.JwtCredential{
+		"kid-1": {Id: "cred-1"},
+	}
+	k.pruneStaleAuth0Kids(nil, "tenant.auth0.com", existing, map[string]bool{})
+
+	missingSince, ok := k.auth0Keys.missingSince["tenant.auth0.com"]
+	if !ok {
+		t.Fatalf("expected a missingSince entry for the host")
+	}
+	if _, tracked := missingSince["kid-1"]; !tracked {
+		t.Fatalf("expected kid-1 to be tracked as missing")
+	}
+}
+
+// TestPruneStaleAuth0KidsClearsTrackingWhenKeyReappears covers the other
+// half of the bookkeeping: a kid that reappears in the JWKS (seenKids) has
+// its missing-since tracking cleared, resetting its grace period.
+func TestPruneStaleAuth0KidsClearsTrackingWhenKeyReappears(t *testing.T) {
+	k := newTestAuth0Controller(3600)
+	k.auth0Keys.missingSince["tenant.auth0.com"] = map[string]time.Time{
+		"kid-1": time.Now().Add(-time.Hour),
+	}
+
+	k.pruneStaleAuth0Kids(nil, "tenant.auth0.com", map[string]kongswagger.JwtCredential{}, map[string]bool{"kid-1": true})
+
+	if _, tracked := k.auth0Keys.missingSince["tenant.auth0.com"]["kid-1"]; tracked {
+		t.Fatalf("expected kid-1's missing-since tracking to be cleared once it reappeared")
+	}
+}
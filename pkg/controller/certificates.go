@@ -0,0 +1,350 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/kong"
+	"gopkg.in/square/go-jose.v2/json"
+	"k8s.io/api/core/v1"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// certFinalizer mirrors kong.Finalizer on Services: it's kept on a TLS
+	// Secret for as long as a Kong certificate derived from it exists, so the
+	// certificate (and its SNIs) are cleaned up before the Secret goes away.
+	certFinalizer = "kong.kolihub.io/certificate-finalizer"
+	// certFingerprintAnnotation records the sha256 of tls.crt+tls.key that
+	// was last synced to Kong, so a resync can tell a rotated Secret from an
+	// unchanged one without comparing key material against Kong's stored
+	// certificate.
+	certFingerprintAnnotation = "kolihub.io/kong-cert-fingerprint"
+	// certHostsAnnotation records, as a JSON array, every SNI this Secret has
+	// been synced to Kong under, so syncSecret knows exactly what to remove
+	// once the Secret (or its last referencing Ingress) is deleted.
+	certHostsAnnotation = "kolihub.io/kong-cert-hosts"
+)
+
+// fingerprintTLSSecret returns a stable, content-addressed fingerprint for a
+// TLS Secret's key material.
+func fingerprintTLSSecret(secret *v1.Secret) string {
+	sum := sha256.Sum256(append(secret.Data["tls.crt"], secret.Data["tls.key"]...))
+	return hex.EncodeToString(sum[:])
+}
+
+// certificateForSNI returns the Kong certificate currently serving the given
+// SNI, if any, so multiple Ingresses targeting the same host share one Kong
+// certificate object instead of each registering a duplicate.
+func certificateForSNI(kongclient *kongswagger.APIClient, sni string) (*kongswagger.Certificate, error) {
+	list, _, err := kongclient.DefaultApi.ListCertificates(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list.Data {
+		for _, s := range c.Snis {
+			if s == sni {
+				cert := c
+				return &cert, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// reconcileCertificateForHost creates or patches the Kong certificate for a
+// single TLS host, reusing any certificate that already serves that SNI, and
+// skipping the create/patch when Kong still serves that SNI and the Secret's
+// fingerprint hasn't changed since the last successful sync. ing, when
+// non-nil, is the owning Ingress to correlate failure/success events to,
+// besides the Secret itself.
+func (k *KongController) reconcileCertificateForHost(kongclient *kongswagger.APIClient, secret *v1.Secret, host string, ing *v1beta1.Ingress) error {
+	objs := []runtime.Object{secret}
+	if ing != nil {
+		objs = append(objs, ing)
+	}
+
+	fingerprint := fingerprintTLSSecret(secret)
+	existing, err := certificateForSNI(kongclient, host)
+	if err != nil {
+		k.recordApplyFailure(ReasonKongCertificateApplyFailed, "certificate", host, err, objs...)
+		return err
+	}
+
+	alreadySynced := existing != nil && secret.Annotations[certFingerprintAnnotation] == fingerprint && secretHasHost(secret, host)
+	if alreadySynced {
+		glog.V(4).Infof("certificate for host %s is up to date (fingerprint %s)", host, fingerprint)
+		return nil
+	}
+
+	cert := kongswagger.Certificate{
+		Cert: string(secret.Data["tls.crt"]),
+		Key:  string(secret.Data["tls.key"]),
+		Snis: []string{host},
+	}
+	options := map[string]interface{}{"certificate": cert}
+
+	if existing == nil {
+		if _, _, err := kongclient.DefaultApi.CreateCertificate(options); err != nil {
+			k.recordApplyFailure(ReasonKongCertificateApplyFailed, "certificate", host, err, objs...)
+			return err
+		}
+	} else {
+		if _, _, err := kongclient.DefaultApi.UpdateCertificate(existing.Id, options); err != nil {
+			k.recordApplyFailure(ReasonKongCertificateApplyFailed, "certificate", host, err, objs...)
+			return err
+		}
+	}
+	k.recordApplySuccess(ReasonKongCertificateApplied, "certificate", host, objs...)
+	return k.patchSecretCertState(secret, fingerprint, host)
+}
+
+// patchSecretCertState records the fingerprint that was just synced and adds
+// host to the set of SNIs the Secret is tracked as owning, plus the
+// certificate finalizer, so a future syncSecret can clean up precisely what
+// was created.
+func (k *KongController) patchSecretCertState(secret *v1.Secret, fingerprint, host string) error {
+	hosts := secretHosts(secret)
+	found := false
+	for _, h := range hosts {
+		if h == host {
+			found = true
+			break
+		}
+	}
+	if !found {
+		hosts = append(hosts, host)
+	}
+	hostsJSON, err := json.Marshal(hosts)
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(
+		`{"metadata": {"annotations": {%q: %q, %q: %s}, "finalizers": ["%s"]}}`,
+		certFingerprintAnnotation, fingerprint, certHostsAnnotation, hostsJSON, certFinalizer,
+	)
+	if _, err := k.client.Core().Secrets(secret.Namespace).Patch(secret.Name, types.StrategicMergePatchType, []byte(patch)); err != nil {
+		return fmt.Errorf("failed patching secret %s/%s with cert state: %s", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+// pruneRemovedCertHosts deletes the Kong certificate for any host that was
+// tracked as synced on a previous pass (secretHosts(secret)) but is no
+// longer in currentHosts, and rewrites certHostsAnnotation down to exactly
+// currentHosts. Without this, removing one host from a multi-host Secret's
+// spec.tls leaves its certificate (and duplicated SNI) behind in Kong
+// forever, since patchSecretCertState only ever adds hosts to the
+// annotation, never drops them.
+func (k *KongController) pruneRemovedCertHosts(kongclient *kongswagger.APIClient, secret *v1.Secret, currentHosts []string) error {
+	current := make(map[string]bool, len(currentHosts))
+	for _, h := range currentHosts {
+		current[h] = true
+	}
+	var removedAny bool
+	for _, host := range secretHosts(secret) {
+		if current[host] {
+			continue
+		}
+		removedAny = true
+		cert, err := certificateForSNI(kongclient, host)
+		if err != nil {
+			return err
+		}
+		if cert == nil {
+			continue
+		}
+		if err := kongclient.DefaultApi.DeleteCertificate(cert.Id); err != nil {
+			return err
+		}
+		glog.Infof("removed stale kong certificate for host %s, no longer in secret %s/%s", host, secret.Namespace, secret.Name)
+	}
+	if !removedAny {
+		return nil
+	}
+	return k.trackSecretCertHosts(secret, currentHosts)
+}
+
+// trackSecretCertHosts overwrites certHostsAnnotation with exactly hosts,
+// for pruneRemovedCertHosts to drop hosts that are no longer desired instead
+// of patchSecretCertState's additive merge.
+func (k *KongController) trackSecretCertHosts(secret *v1.Secret, hosts []string) error {
+	hostsJSON, err := json.Marshal(hosts)
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"metadata": {"annotations": {%q: %s}}}`, certHostsAnnotation, hostsJSON)
+	if _, err := k.client.Core().Secrets(secret.Namespace).Patch(secret.Name, types.StrategicMergePatchType, []byte(patch)); err != nil {
+		return fmt.Errorf("failed pruning stale cert hosts from secret %s/%s: %s", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+func secretHosts(secret *v1.Secret) []string {
+	var hosts []string
+	if raw, ok := secret.Annotations[certHostsAnnotation]; ok {
+		json.Unmarshal([]byte(raw), &hosts)
+	}
+	return hosts
+}
+
+func secretHasHost(secret *v1.Secret, host string) bool {
+	for _, h := range secretHosts(secret) {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsHostsForSecret returns every host, across every Ingress in the Secret's
+// namespace, whose spec.tls references this Secret by name.
+func (k *KongController) tlsHostsForSecret(secret *v1.Secret) []string {
+	var hosts []string
+	cache.ListAllByNamespace(k.infIng.GetIndexer(), secret.Namespace, labels.Everything(), func(obj interface{}) {
+		ing := obj.(*v1beta1.Ingress)
+		for _, t := range ing.Spec.TLS {
+			if t.SecretName == secret.Name {
+				hosts = append(hosts, t.Hosts...)
+			}
+		}
+	})
+	return hosts
+}
+
+// newSecretInformer watches v1.Secret cluster-wide and enqueues TLS secrets
+// on every add/update/delete so rotating a Secret (or deleting the last
+// Ingress referencing it) reconciles Kong without waiting for the owning
+// Ingress to resync on its own.
+func newSecretInformer(k *KongController, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	inf := cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(k.client.Core().RESTClient(), "secrets", metav1.NamespaceAll, fields.Everything()),
+		&v1.Secret{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	isTLS := func(obj interface{}) bool {
+		secret, ok := obj.(*v1.Secret)
+		return ok && secret.Type == v1.SecretTypeTLS
+	}
+	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if isTLS(obj) {
+				k.secretQueue.Add(obj)
+			}
+		},
+		UpdateFunc: func(o, n interface{}) {
+			old := o.(*v1.Secret)
+			new := n.(*v1.Secret)
+			if old.ResourceVersion != new.ResourceVersion && isTLS(n) {
+				k.secretQueue.Add(n)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if isTLS(obj) {
+				k.secretQueue.Add(obj)
+			}
+		},
+	})
+	return inf
+}
+
+// syncSecret reconciles the Kong certificate(s) derived from a single TLS
+// Secret: it re-syncs every host still referencing it, and once the Secret
+// is deleted (or the last referencing Ingress is gone) it removes the Kong
+// certificates it owns and drops the finalizer so the Secret can finish
+// deleting.
+func (k *KongController) syncSecret(key string, numRequeues int) error {
+	obj, exists, err := k.infSecret.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		glog.V(4).Infof("%s - secret doesn't exist", key)
+		return nil
+	}
+	secret := obj.(*v1.Secret)
+	hosts := k.tlsHostsForSecret(secret)
+
+	if secret.DeletionTimestamp == nil && len(hosts) > 0 {
+		return k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+			for _, host := range hosts {
+				if err := k.reconcileCertificateForHost(kongclient, secret, host, nil); err != nil {
+					return err
+				}
+			}
+			// hosts is only what's still in spec.tls; any host tracked from a
+			// previous sync (secretHosts(secret)) that isn't in hosts anymore
+			// was removed from spec.tls and needs its Kong certificate (and
+			// tracking annotation entry) cleaned up too, or it's left behind
+			// as a stale cert with a duplicate SNI.
+			return k.pruneRemovedCertHosts(kongclient, secret, hosts)
+		})
+	}
+
+	// Either the Secret is being deleted or no Ingress references it
+	// anymore: remove every certificate it owns and release the finalizer.
+	if !hasFinalizer(secret.Finalizers, certFinalizer) {
+		return nil
+	}
+	err = k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+		for _, host := range secretHosts(secret) {
+			cert, err := certificateForSNI(kongclient, host)
+			if err != nil {
+				return err
+			}
+			if cert == nil {
+				continue
+			}
+			if err := kongclient.DefaultApi.DeleteCertificate(cert.Id); err != nil {
+				return err
+			}
+			glog.Infof("removed kong certificate for host %s (secret %s/%s)", host, secret.Namespace, secret.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		glog.Errorf("%s - failed removing kong certificates for secret: %s", key, err)
+		return err
+	}
+
+	remaining := removeFinalizer(secret.Finalizers, certFinalizer)
+	remainingJSON, err := json.Marshal(remaining)
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(`{"metadata": {"finalizers": %s}}`, remainingJSON)
+	if _, err := k.client.Core().Secrets(secret.Namespace).Patch(secret.Name, types.StrategicMergePatchType, []byte(patch)); err != nil {
+		return fmt.Errorf("failed removing certificate finalizer from secret %s/%s: %s", secret.Namespace, secret.Name, err)
+	}
+	return nil
+}
+
+func hasFinalizer(finalizers []string, name string) bool {
+	for _, f := range finalizers {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string, name string) []string {
+	out := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if f != name {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+)
+
+func secretWithKeyMaterial(crt, key string) *v1.Secret {
+	return &v1.Secret{Data: map[string][]byte{"tls.crt": []byte(crt), "tls.key": []byte(key)}}
+}
+
+// TestFingerprintTLSSecretStableAndContentAddressed covers the fingerprint-
+// skip logic reconcileCertificateForHost relies on: the same key material
+// must always fingerprint to the same value, and any change to either
+// tls.crt or tls.key must change it.
+func TestFingerprintTLSSecretStableAndContentAddressed(t *testing.T) {
+	a := secretWithKeyMaterial("cert-a", "key-a")
+	b := secretWithKeyMaterial("cert-a", "key-a")
+	if fingerprintTLSSecret(a) != fingerprintTLSSecret(b) {
+		t.Fatalf("expected identical key material to produce identical fingerprints")
+	}
+
+	changedCert := secretWithKeyMaterial("cert-b", "key-a")
+	if fingerprintTLSSecret(a) == fingerprintTLSSecret(changedCert) {
+		t.Fatalf("expected a changed tls.crt to change the fingerprint")
+	}
+
+	changedKey := secretWithKeyMaterial("cert-a", "key-b")
+	if fingerprintTLSSecret(a) == fingerprintTLSSecret(changedKey) {
+		t.Fatalf("expected a changed tls.key to change the fingerprint")
+	}
+}
+
+// TestSecretHasHostTracksAnnotationState covers the other half of the
+// fingerprint-skip check: whether a host is already recorded in
+// certHostsAnnotation.
+func TestSecretHasHostTracksAnnotationState(t *testing.T) {
+	secret := &v1.Secret{}
+	if secretHasHost(secret, "example.com") {
+		t.Fatalf("expected a Secret with no hosts annotation to report no hosts as synced")
+	}
+
+	secret.Annotations = map[string]string{certHostsAnnotation: `["example.com","foo.example.com"]`}
+	if !secretHasHost(secret, "example.com") {
+		t.Fatalf("expected example.com to be recorded as synced")
+	}
+	if secretHasHost(secret, "other.example.com") {
+		t.Fatalf("expected other.example.com, which isn't in the annotation, to report unsynced")
+	}
+}
+
+// TestPruneRemovedCertHostsNoopWhenNothingRemoved covers
+// pruneRemovedCertHosts's fast path: when currentHosts already covers every
+// previously-tracked host, it must return without even touching the Kong
+// client (passing a nil kongclient here would panic if it did).
+func TestPruneRemovedCertHostsNoopWhenNothingRemoved(t *testing.T) {
+	k := &KongController{}
+	secret := &v1.Secret{}
+	secret.Annotations = map[string]string{certHostsAnnotation: `["example.com","foo.example.com"]`}
+
+	if err := k.pruneRemovedCertHosts(nil, secret, []string{"example.com", "foo.example.com"}); err != nil {
+		t.Fatalf("expected no error when no tracked host was removed, got: %s", err)
+	}
+}
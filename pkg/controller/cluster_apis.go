@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/koli/kong-ingress/pkg/kong"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// clusterAPICache remembers the kong.API set last computed for each
+// Ingress, keyed by namespace/name. The entity backend, unlike the
+// declarative backend, has no whole-cluster config it can fetch back from
+// Kong (EntityBackend.Fetch always returns nil), so this cache is what lets
+// it still assemble a full-cluster snapshot to mirror to Konnect instead of
+// leaking only the currently-syncing Ingress's own subset.
+type clusterAPICache struct {
+	mu        sync.Mutex
+	byIngress map[types.NamespacedName][]*kong.API
+}
+
+func newClusterAPICache() *clusterAPICache {
+	return &clusterAPICache{byIngress: make(map[types.NamespacedName][]*kong.API)}
+}
+
+// set records ing's current desired APIs, replacing whatever was recorded
+// for it on the previous sync.
+func (c *clusterAPICache) set(ing types.NamespacedName, apis []*kong.API) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byIngress[ing] = apis
+}
+
+// drop removes ing's entry entirely, for when it no longer has any rules or
+// has been deleted.
+func (c *clusterAPICache) drop(ing types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.byIngress, ing)
+}
+
+// merged flattens every cached Ingress's APIs into one slice, deduplicated
+// by API name and sorted for a byte-stable marshal -- the same merge
+// syncDeclarativeAPIs does against Kong's live /config, just sourced from
+// this in-memory cache instead.
+func (c *clusterAPICache) merged() []*kong.API {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	byName := make(map[string]*kong.API)
+	for _, apis := range c.byIngress {
+		for _, api := range apis {
+			byName[api.Name] = api
+		}
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	merged := make([]*kong.API, len(names))
+	for i, name := range names {
+		merged[i] = byName[name]
+	}
+	return merged
+}
@@ -12,4 +12,106 @@ type Config struct {
 	AutoClaim      bool
 	WipeOnDelete   bool
 	ResyncOnFailed int64
+
+	// KongAdminSvcNamespace/KongAdminSvcName point the controller at a
+	// headless Kubernetes Service fronting a Kong Admin API deployment
+	// instead of a single KongAdminHost. When set, the controller discovers
+	// every ready endpoint behind the Service and fans out writes to each
+	// one. KongAdminSvcPortName selects the named port to use when the
+	// Service exposes more than one; if empty the first port is used.
+	KongAdminSvcNamespace string
+	KongAdminSvcName      string
+	KongAdminSvcPortName  string
+	// KongAdminReResolveInterval controls how often the endpoint pool is
+	// refreshed outside of informer-driven updates. Defaults to 30s.
+	KongAdminReResolveInterval int64
+
+	// KongAdminURLs is a comma-separated list of `host:port` Kong Admin API
+	// addresses, for deployments that front Kong with a known, static set of
+	// endpoints instead of Service/Endpoints discovery. Ignored when
+	// KongAdminSvcName or KongAdminServiceSelector is set.
+	KongAdminURLs string
+	// KongAdminServiceSelector/KongAdminServiceNamespace discover Kong Admin
+	// API endpoints across every headless Service matching the label
+	// selector in the namespace, merging them into a single pool. Use this
+	// instead of KongAdminSvcName when Kong is fronted by more than one
+	// Service, e.g. one per availability zone.
+	KongAdminServiceSelector  string
+	KongAdminServiceNamespace string
+	// AdminSyncQuorum is the minimum number of discovered admin instances
+	// that must apply a change successfully for it to be considered
+	// "programmed". Zero (the default) requires every instance to succeed.
+	AdminSyncQuorum int
+
+	// Auth0RefreshInterval controls how often the background refresher
+	// re-polls each Auth0 host's JWKS to pick up rotated/added signing keys.
+	// Defaults to 1h.
+	Auth0RefreshInterval int64
+	// Auth0KeyGracePeriod is how long a signing key (kid) must be missing
+	// from a tenant's JWKS before its JwtCredential is deleted. Defaults to
+	// 24h.
+	Auth0KeyGracePeriod int64
+
+	// KonnectEnabled mirrors every successful local Kong admin push to
+	// Konnect, for users who self-host Kong but still want Konnect's
+	// fleet-wide observability and config backup.
+	KonnectEnabled bool
+	// KonnectRuntimeGroupID is the Konnect Runtime Group to mirror
+	// configuration into.
+	KonnectRuntimeGroupID string
+	// KonnectAddress is the base URL of the Konnect config-upload API.
+	KonnectAddress string
+	// KonnectTLS holds the client certificate/key Konnect issued for
+	// KonnectRuntimeGroupID.
+	KonnectTLS rest.TLSClientConfig
+
+	// LicenseSource is a URL the controller polls for an Enterprise license
+	// to inject into every configured Kong Admin API instance. Ignored when
+	// LicenseSecretRef is set.
+	LicenseSource string
+	// LicenseSecretRef is a "namespace/name" Secret the controller watches
+	// for an Enterprise license, read from its "license" key. Takes
+	// precedence over LicenseSource, and hot-reloads the license on Secret
+	// change instead of waiting for the next LicensePollInterval tick.
+	LicenseSecretRef string
+	// LicensePollInterval controls how often the license agent re-fetches
+	// from LicenseSource/LicenseSecretRef outside of Secret-watch events.
+	// Defaults to 1h.
+	LicensePollInterval int64
+
+	// SyncBackend selects how translated Kong configuration is pushed:
+	// "entity" (the default) writes one admin API entity at a time, the
+	// controller's original behavior; "declarative" renders the full
+	// desired state and diffs it against a fetched snapshot before issuing
+	// a single /config reload, deck-style.
+	SyncBackend string
+
+	// WebhookBindAddress is the address the validating admission webhook's
+	// HTTPS listener binds to, e.g. ":8443". Leaving it empty disables the
+	// webhook subsystem entirely.
+	WebhookBindAddress string
+	// WebhookCertPath/WebhookKeyPath name a cert/key pair on disk the
+	// webhook watches and hot-reloads on change (e.g. a cert-manager-
+	// managed Secret volume). Ignored when WebhookCertSecret is set.
+	WebhookCertPath string
+	WebhookKeyPath  string
+	// WebhookCertSecret is a "namespace/name" Secret, with "tls.crt"/
+	// "tls.key" keys, the webhook watches instead of a mounted cert/key
+	// pair. Takes precedence over WebhookCertPath/WebhookKeyPath, mirroring
+	// LicenseSecretRef's precedence over LicenseSource.
+	WebhookCertSecret string
+	// WebhookConfigName is the ValidatingWebhookConfiguration whose
+	// caBundle the leader-elected reconciler keeps in sync with
+	// WebhookCertPath's (or WebhookCertSecret's) CA certificate.
+	WebhookConfigName string
+
+	// LeaderElect gates every Kong-admin-writing sync worker (the ingress/
+	// service/secret/route/plugin queues, the Auth0 refresher, and the
+	// license agent) behind a leader election, so that running more than one
+	// controller replica against the same admin pool doesn't mean every
+	// replica independently fans out writes to every Kong instance at once.
+	// Standby replicas still run their informers and caches, they just don't
+	// write until elected. Defaults to false for single-replica deployments,
+	// where electing a leader would only add a dependency with no benefit.
+	LeaderElect bool
 }
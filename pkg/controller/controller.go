@@ -2,10 +2,8 @@ package controller
 
 import (
 	"bytes"
-	"crypto/x509"
-	"encoding/pem"
+	"context"
 	"fmt"
-	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -13,9 +11,12 @@ import (
 	"time"
 
 	"github.com/golang/glog"
-	auth0 "github.com/jgensler8/go-auth0/generated/client"
 	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/admission"
 	"github.com/koli/kong-ingress/pkg/kong"
+	"github.com/koli/kong-ingress/pkg/konnect"
+	"github.com/koli/kong-ingress/pkg/licenseagent"
+	"github.com/koli/kong-ingress/pkg/translator"
 	"gopkg.in/square/go-jose.v2/json"
 	"k8s.io/api/core/v1"
 	v1beta1 "k8s.io/api/extensions/v1beta1"
@@ -23,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -43,6 +45,18 @@ var (
 	jwtAuth0DomainAnnotation = "kolihub.io/x-jwt-auth0-domain"
 )
 
+// Kong's own defaults for the Service/Upstream fields a KongIngress can
+// override, used as the def argument to the corresponding Resolved*
+// accessor so an Ingress with no KongIngress override behaves exactly like
+// it did before overrides existed.
+const (
+	kongDefaultRetries        = 5
+	kongDefaultConnectTimeout = 60000
+	kongDefaultWriteTimeout   = 60000
+	kongDefaultReadTimeout    = 60000
+	kongDefaultHashOn         = "none"
+)
+
 // KongController watches the kubernetes api server and adds/removes apis on Kong
 type KongController struct {
 	client     kubernetes.Interface
@@ -50,16 +64,76 @@ type KongController struct {
 	kongcli    *kong.CoreClient
 	kongclient *kongswagger.APIClient
 
-	infIng cache.SharedIndexInformer
-	infSvc cache.SharedIndexInformer
-	infDom cache.SharedIndexInformer
+	infIng    cache.SharedIndexInformer
+	infSvc    cache.SharedIndexInformer
+	infDom    cache.SharedIndexInformer
+	infSecret cache.SharedIndexInformer
+	infRoute  cache.SharedIndexInformer
+	infPlugin cache.SharedIndexInformer
+	// infKongIngress backs resolveKongIngressOverrides; it has no sync queue
+	// since KongIngress is only ever resolved by name from a referencing
+	// Ingress.
+	infKongIngress cache.SharedIndexInformer
+
+	// adminPool is non-nil when the controller was configured with
+	// KongAdminSvcName, KongAdminServiceSelector or KongAdminURLs, in which
+	// case every admin API write fans out to the
+	// whole discovered pool instead of the single kongcli/kongclient pair.
+	adminPool *AdminPool
 
 	cfg *Config
 
-	ingQueue *TaskQueue
-	domQueue *TaskQueue
-	svcQueue *TaskQueue
-	recorder record.EventRecorder
+	ingQueue    *TaskQueue
+	domQueue    *TaskQueue
+	svcQueue    *TaskQueue
+	secretQueue *TaskQueue
+	routeQueue  *TaskQueue
+	pluginQueue *TaskQueue
+	recorder    record.EventRecorder
+
+	// pluginCache memoizes parsed `kolihub.io/plugin-*` annotations so
+	// ConfigurePluginsForAPI doesn't re-unmarshal them on every resync.
+	pluginCache *pluginParseCache
+
+	// clusterAPIs tracks every Ingress's last-synced kong.API set, so the
+	// entity backend can assemble a whole-cluster snapshot for the Konnect
+	// mirror; see clusterAPICache's doc comment.
+	clusterAPIs *clusterAPICache
+
+	// auth0Keys tracks, per Auth0 host, how long each signing key (kid) has
+	// been missing from the tenant's JWKS, so stale credentials are only
+	// removed after a grace period instead of on the first missed refresh.
+	auth0Keys *auth0KeyTracker
+
+	// konnectClient mirrors successfully-applied Kong configuration to
+	// Konnect; nil when KonnectEnabled is false. konnectQueue decouples the
+	// mirror from the local sync loop so a Konnect outage never blocks (or
+	// is blocked by) writes to the local Kong admin API.
+	konnectClient *konnect.Client
+	konnectQueue  chan konnectSyncRequest
+
+	// licenseAgent polls for an Enterprise license (LicenseSource or
+	// LicenseSecretRef) and injects it into every configured Kong Admin API
+	// instance; nil when neither is configured. infLicenseSecret is only
+	// set (and only watches the single named Secret) when LicenseSecretRef
+	// is used, so a license rotation is picked up immediately instead of
+	// waiting for the next poll.
+	licenseAgent     *licenseagent.Agent
+	infLicenseSecret cache.SharedIndexInformer
+
+	// translatorBackend applies the Kong configuration computed by
+	// syncIngress, selected by cfg.SyncBackend: entity-at-a-time writes (the
+	// original behavior) or a deck-style declarative reload.
+	translatorBackend translator.Backend
+
+	// webhookCerts serves the validating admission webhook's TLS
+	// certificate, hot-reloaded from disk or WebhookCertSecret; nil when
+	// WebhookBindAddress isn't configured. infWebhookCertSecret mirrors
+	// infLicenseSecret: only set (and only watching the single named
+	// Secret) when WebhookCertSecret is used.
+	webhookCerts            *admission.CertWatcher
+	infWebhookCertSecret    cache.SharedIndexInformer
+	webhookConfigReconciler *admission.WebhookConfigReconciler
 }
 
 // NewKongController creates a new KongController
@@ -77,16 +151,75 @@ func NewKongController(
 		Interface: v1core.New(client.Core().RESTClient()).Events(""),
 	})
 	kc := &KongController{
-		client:     client,
-		extClient:  extClient,
-		kongcli:    kongcli,
-		kongclient: kongclient,
-		recorder:   eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "kong-controller"}),
-		cfg:        cfg,
+		client:        client,
+		extClient:     extClient,
+		kongcli:       kongcli,
+		kongclient:    kongclient,
+		recorder:      eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "kong-controller"}),
+		cfg:           cfg,
+		pluginCache:   newPluginParseCache(),
+		clusterAPIs:   newClusterAPICache(),
+		auth0Keys:     newAuth0KeyTracker(),
+		konnectClient: newKonnectClient(cfg),
+		konnectQueue:  make(chan konnectSyncRequest, konnectQueueSize),
 	}
 	kc.ingQueue = NewTaskQueue(kc.syncIngress, "kong_ingress_queue")
 	kc.domQueue = NewTaskQueue(kc.syncDomain, "kong_domain_queue")
 	kc.svcQueue = NewTaskQueue(kc.syncServices, "kong_service_queue")
+	kc.secretQueue = NewTaskQueue(kc.syncSecret, "kong_secret_queue")
+	kc.infSecret = newSecretInformer(kc, resyncPeriod)
+	kc.routeQueue = NewTaskQueue(kc.syncRoute, "kong_route_queue")
+	kc.pluginQueue = NewTaskQueue(kc.syncKongPlugin, "kong_plugin_queue")
+	kc.infRoute = newKongRouteInformer(kc, resyncPeriod)
+	kc.infPlugin = newKongPluginInformer(kc, resyncPeriod)
+	kc.infKongIngress = newKongIngressInformer(kc, resyncPeriod)
+
+	// Precedence mirrors specificity: a single named Service wins over the
+	// selector-based multi-Service mode, which in turn wins over a static
+	// address list, so enabling one doesn't silently change the others.
+	switch {
+	case cfg.KongAdminSvcName != "":
+		kc.adminPool = NewAdminPool(
+			client,
+			cfg.KongAdminSvcNamespace,
+			cfg.KongAdminSvcName,
+			cfg.KongAdminSvcPortName,
+			cfg.TLSConfig,
+			resyncPeriod,
+		)
+	case cfg.KongAdminServiceSelector != "":
+		kc.adminPool = NewAdminPoolFromSelector(
+			client,
+			cfg.KongAdminServiceNamespace,
+			cfg.KongAdminServiceSelector,
+			cfg.KongAdminSvcPortName,
+			cfg.TLSConfig,
+			resyncPeriod,
+		)
+	case cfg.KongAdminURLs != "":
+		pool, err := NewAdminPoolFromURLs(cfg.KongAdminURLs, cfg.TLSConfig)
+		if err != nil {
+			glog.Errorf("failed building admin pool from KongAdminURLs: %s", err)
+		} else {
+			kc.adminPool = pool
+		}
+	}
+	if kc.adminPool != nil {
+		kc.adminPool.SetQuorum(cfg.AdminSyncQuorum)
+	}
+
+	kc.licenseAgent = newLicenseAgent(kc, cfg)
+	if cfg.LicenseSecretRef != "" {
+		kc.infLicenseSecret = newLicenseSecretInformer(kc, resyncPeriod)
+	}
+
+	kc.webhookCerts = newWebhookCertWatcher(kc, cfg)
+	if cfg.WebhookCertSecret != "" {
+		kc.infWebhookCertSecret = newWebhookCertSecretInformer(kc, resyncPeriod)
+	}
+	kc.webhookConfigReconciler = newWebhookConfigReconciler(kc, cfg)
+
+	kc.translatorBackend = newTranslatorBackend(kc, cfg)
 
 	kc.infIng = cache.NewSharedIndexInformer(
 		cache.NewListWatchFromClient(client.Extensions().RESTClient(), "ingresses", metav1.NamespaceAll, fields.Everything()),
@@ -113,6 +246,11 @@ func NewKongController(
 		},
 		DeleteFunc: func(obj interface{}) {
 			ing := obj.(*v1beta1.Ingress)
+			// The key is gone from the store by the time syncIngress runs for
+			// this delete, so evict pluginCache's entries here instead, while
+			// the Ingress's UID is still available.
+			kc.pluginCache.dropAll(ing.UID)
+			kc.clusterAPIs.drop(types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name})
 			if !isKongIngress(ing) {
 				glog.Infof("ignoring delete for ingress %v based on annotation %v", ing.Name, ingressClassKey)
 				return
@@ -166,22 +304,80 @@ func (k *KongController) Run(workers int, stopc <-chan struct{}) {
 	defer k.ingQueue.shutdown()
 	defer k.domQueue.shutdown()
 	defer k.svcQueue.shutdown()
+	defer k.secretQueue.shutdown()
+	defer k.routeQueue.shutdown()
+	defer k.pluginQueue.shutdown()
 
 	go k.infIng.Run(stopc)
 	go k.infSvc.Run(stopc)
 	go k.infDom.Run(stopc)
+	go k.infSecret.Run(stopc)
+	go k.infRoute.Run(stopc)
+	go k.infPlugin.Run(stopc)
+	go k.infKongIngress.Run(stopc)
 
-	if !cache.WaitForCacheSync(stopc, k.infIng.HasSynced, k.infSvc.HasSynced) {
+	if !cache.WaitForCacheSync(stopc, k.infIng.HasSynced, k.infSvc.HasSynced, k.infSecret.HasSynced, k.infRoute.HasSynced, k.infPlugin.HasSynced, k.infKongIngress.HasSynced) {
 		return
 	}
 
-	// start up your worker threads based on threadiness.
-	for i := 0; i < workers; i++ {
-		// runWorker will loop until "something bad" happens.
-		// The .Until will then rekick the worker after one second
-		go k.ingQueue.run(time.Second, stopc)
-		go k.domQueue.run(time.Second, stopc)
-		go k.svcQueue.run(time.Second, stopc)
+	if k.adminPool != nil {
+		go k.adminPool.Run(time.Duration(k.cfg.KongAdminReResolveInterval)*time.Second, stopc)
+		glog.Infof("waiting for at least one Kong admin endpoint to become reachable ...")
+		if !k.adminPool.WaitUntilReady(stopc) {
+			return
+		}
+		glog.Infof("admin pool ready with %d endpoint(s)", k.adminPool.Len())
+	}
+
+	if k.licenseAgent != nil && k.infLicenseSecret != nil {
+		go k.infLicenseSecret.Run(stopc)
+		cache.WaitForCacheSync(stopc, k.infLicenseSecret.HasSynced)
+	}
+
+	// startSyncWorkers starts every worker that writes Kong configuration
+	// through forEachAdmin (the sync queues, the Auth0 refresher, and the
+	// license agent). Wrapped in a closure so it can be run either directly
+	// (the pre-LeaderElect behavior) or gated behind leader election below:
+	// with more than one replica pointed at the same admin pool, running it
+	// unconditionally on every replica means every replica independently
+	// fans out writes to every Kong instance at once.
+	startSyncWorkers := func(workerStopc <-chan struct{}) {
+		// start up your worker threads based on threadiness.
+		for i := 0; i < workers; i++ {
+			// runWorker will loop until "something bad" happens.
+			// The .Until will then rekick the worker after one second
+			go k.ingQueue.run(time.Second, workerStopc)
+			go k.domQueue.run(time.Second, workerStopc)
+			go k.svcQueue.run(time.Second, workerStopc)
+			go k.secretQueue.run(time.Second, workerStopc)
+			go k.routeQueue.run(time.Second, workerStopc)
+			go k.pluginQueue.run(time.Second, workerStopc)
+		}
+
+		go k.runAuth0Refresh(workerStopc)
+		if k.licenseAgent != nil {
+			go k.licenseAgent.Run(time.Duration(k.cfg.LicensePollInterval)*time.Second, workerStopc)
+		}
+	}
+	if k.cfg.LeaderElect {
+		go runLeaderElectedSync(newSyncLeaderLock(k, k.cfg), startSyncWorkers, stopc)
+	} else {
+		startSyncWorkers(stopc)
+	}
+
+	if k.konnectClient != nil {
+		go k.runKonnectMirror(stopc)
+	}
+	if k.webhookCerts != nil {
+		if k.infWebhookCertSecret != nil {
+			go k.infWebhookCertSecret.Run(stopc)
+			cache.WaitForCacheSync(stopc, k.infWebhookCertSecret.HasSynced)
+		}
+		go k.webhookCerts.Run(time.Minute, stopc)
+		go k.runWebhookServer(stopc)
+	}
+	if k.webhookConfigReconciler != nil {
+		go k.webhookConfigReconciler.Run(stopc)
 	}
 
 	// run will loop until "something bad" happens.
@@ -190,6 +386,18 @@ func (k *KongController) Run(workers int, stopc <-chan struct{}) {
 	glog.Infof("Shutting down Kong controller")
 }
 
+// forEachAdmin issues fn against every admin API instance known to the
+// controller. When an AdminPool is configured it fans out to every
+// discovered endpoint and aggregates failures; otherwise it falls back to
+// the single kongcli/kongclient pair the controller was constructed with, so
+// existing single-instance deployments keep working unmodified.
+func (k *KongController) forEachAdmin(fn func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error) error {
+	if k.adminPool != nil {
+		return k.adminPool.ForEach(fn)
+	}
+	return fn(k.kongcli, k.kongclient)
+}
+
 // garbage collect kong apis
 func (k *KongController) syncServices(key string, numRequeues int) error {
 	obj, exists, err := k.infSvc.GetStore().GetByKey(key)
@@ -213,15 +421,21 @@ func (k *KongController) syncServices(key string, numRequeues int) error {
 		upstreamURL := k.getUpstream(proto, svc.Namespace, svc.Name, port.Port)
 		glog.V(4).Infof("%s - gc=true, cleaning up kong apis from upstream %s", key, upstreamURL)
 		params := url.Values{"upstream_url": []string{upstreamURL}}
-		apiList, err := k.kongcli.API().List(params)
-		if err != nil {
-			return fmt.Errorf("gc=true, failed listing apis [%s]", err)
-		}
-		for _, api := range apiList.Items {
-			glog.V(4).Infof("%s - gc=true, removing kong api %s[%s]", key, api.Name, api.UID)
-			if err := k.kongcli.API().Delete(api.Name); err != nil {
-				return fmt.Errorf("gc=true, failed removing kong api %s, [%s]", api.Name, err)
+		err := k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+			apiList, err := kongcli.API().List(params)
+			if err != nil {
+				return fmt.Errorf("gc=true, failed listing apis [%s]", err)
+			}
+			for _, api := range apiList.Items {
+				glog.V(4).Infof("%s - gc=true, removing kong api %s[%s]", key, api.Name, api.UID)
+				if err := kongcli.API().Delete(api.Name); err != nil {
+					return fmt.Errorf("gc=true, failed removing kong api %s, [%s]", api.Name, err)
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 		// remove the finalizer
 		if _, err := k.client.Core().Services(svc.Namespace).Patch(
@@ -235,96 +449,184 @@ func (k *KongController) syncServices(key string, numRequeues int) error {
 	return nil
 }
 
-func (k *KongController) ConfigurePluginsForAPI(uuid string, ing *v1beta1.Ingress) error {
+// parsePluginAnnotation unmarshals a single `kolihub.io/plugin-*` annotation
+// into a kongswagger.Plugin, consulting the controller's pluginCache first so
+// a resync that doesn't touch the Ingress doesn't re-parse it.
+func (k *KongController) parsePluginAnnotation(ing *v1beta1.Ingress, a, annotationValue, pluginname string) (kongswagger.Plugin, error) {
+	key := pluginParseCacheKey{ingUID: ing.UID, annotation: a, resourceVersion: ing.ResourceVersion}
+	if entry, ok := k.pluginCache.get(key); ok {
+		return entry.plugin, entry.err
+	}
+
+	plugin := kongswagger.Plugin{Name: pluginname}
+	var iplugin interface{}
 	var err error
+	switch pluginname {
+	case "key-auth":
+		config := kongswagger.PluginConfigKeyAuth{}
+		err = json.Unmarshal([]byte(annotationValue), &config)
+		iplugin = config
+	case "cors":
+		config := kongswagger.PluginConfigCors{}
+		err = json.Unmarshal([]byte(annotationValue), &config)
+		iplugin = config
+	case "jwt":
+		config := kongswagger.PluginConfigJwt{}
+		err = json.Unmarshal([]byte(annotationValue), &config)
+		iplugin = config
+	case "rate-limiting":
+		config := kongswagger.PluginConfigRateLimiting{}
+		err = json.Unmarshal([]byte(annotationValue), &config)
+		iplugin = config
+	default:
+		err = fmt.Errorf("Invlaid plugin '%s' specificied for ing/%s/%s with annotation %s", pluginname, ing.Namespace, ing.Name, a)
+	}
+	if err == nil {
+		plugin.Config = &iplugin
+	}
+	k.pluginCache.set(key, pluginParseCacheEntry{plugin: plugin, err: err})
+	return plugin, err
+}
+
+// normalizePluginConfig round-trips a plugin config through JSON so that
+// reflect.DeepEqual compares the same shape Kong itself stores (a
+// map[string]interface{}) instead of tripping over nil-vs-empty-value
+// differences between the typed annotation config and the typed API
+// response.
+func normalizePluginConfig(config interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// ConfigurePluginsForAPI reconciles the plugins attached to a Kong API with
+// the `kolihub.io/plugin-*` annotations on the Ingress: plugins whose config
+// drifted are patched, plugins no longer requested are deleted, and
+// first-time plugins are created. Editing a plugin annotation now takes
+// effect on the next resync instead of requiring the API to be deleted.
+func (k *KongController) ConfigurePluginsForAPI(kongclient *kongswagger.APIClient, uuid string, ing *v1beta1.Ingress) error {
+	desired := make(map[string]kongswagger.Plugin)
 	for a, annotationValue := range ing.Annotations {
-		if strings.HasPrefix(a, pluginPrefix) {
-			pluginname := strings.TrimPrefix(a, pluginPrefix)
-			plugin := kongswagger.Plugin{
-				Name: pluginname,
-			}
-			var iplugin interface{}
-			if pluginname == "key-auth" {
-				config := kongswagger.PluginConfigKeyAuth{}
-				err = json.Unmarshal([]byte(annotationValue), &config)
-				iplugin = config
-			} else if pluginname == "cors" {
-				config := kongswagger.PluginConfigCors{}
-				err = json.Unmarshal([]byte(annotationValue), &config)
-				iplugin = config
-			} else if pluginname == "jwt" {
-				config := kongswagger.PluginConfigJwt{}
-				err = json.Unmarshal([]byte(annotationValue), &config)
-				iplugin = config
-			} else if pluginname == "rate-limiting" {
-				config := kongswagger.PluginConfigRateLimiting{}
-				err = json.Unmarshal([]byte(annotationValue), &config)
-				iplugin = config
-			} else {
-				err := fmt.Errorf("Invlaid plugin '%s' specificied for ing/%s/%s with annotation %s", pluginname, ing.Namespace, ing.Name, a)
-				glog.Error(err)
-				return err
-			}
-			if err != nil {
-				glog.Infof("Failed to unmarshal plugin config for ing/%s/%s with annotation %s", ing.Namespace, ing.Name, a)
-				return err
-			}
-			plugin.Config = &iplugin
+		if !strings.HasPrefix(a, pluginPrefix) {
+			continue
+		}
+		pluginname := strings.TrimPrefix(a, pluginPrefix)
+		plugin, err := k.parsePluginAnnotation(ing, a, annotationValue, pluginname)
+		if err != nil {
+			glog.Errorf("Failed to parse plugin annotation for ing/%s/%s with annotation %s: %s", ing.Namespace, ing.Name, a, err)
+			k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", pluginname, err, ing)
+			return err
+		}
+		desired[pluginname] = plugin
+	}
 
-			params := map[string]interface{}{
-				"plugin": plugin,
-			}
+	refPlugins, err := k.resolvePluginRefs(ing)
+	if err != nil {
+		return err
+	}
+	for name, plugin := range refPlugins {
+		desired[name] = plugin
+	}
 
-			list, _, err := k.kongclient.DefaultApi.ListPlugins(uuid)
-			if err != nil {
-				glog.Infof("Failed to list plugins for API %s", uuid)
+	return k.reconcilePlugins(kongclient, uuid, desired, ing)
+}
+
+// reconcilePlugins is the list+diff+patch/create/delete core shared by
+// ConfigurePluginsForAPI and configurePluginsForRoute: given the full
+// desired set of plugins for a Kong API/Route uuid, it creates what's
+// missing, patches what drifted, and deletes what's no longer desired,
+// instead of blindly creating every entry in desired every time (which
+// fails with a conflict on every resync once a plugin already exists).
+// obj is passed through to recordApplyFailure/recordApplySuccess purely for
+// event attribution.
+func (k *KongController) reconcilePlugins(kongclient *kongswagger.APIClient, uuid string, desired map[string]kongswagger.Plugin, obj runtime.Object) error {
+	list, _, err := kongclient.DefaultApi.ListPlugins(uuid)
+	if err != nil {
+		glog.Infof("Failed to list plugins for API %s", uuid)
+		k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", uuid, err, obj)
+		return err
+	}
+
+	existing := make(map[string]kongswagger.Plugin)
+	for _, p := range list.Data {
+		existing[p.Name] = p
+	}
+
+	for name, plugin := range desired {
+		params := map[string]interface{}{"plugin": plugin}
+		current, found := existing[name]
+		if !found {
+			if _, _, err := kongclient.DefaultApi.CreatePlugin(uuid, params); err != nil {
+				glog.Infof("Failed to create plugin %s for API (%s)", name, uuid)
+				k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", name, err, obj)
 				return err
 			}
-			found := false
-			for _, p := range list.Data {
-				if p.Name == plugin.Name {
-					glog.Infof("Plugin (%s) already configured for API (%s). Note that new configuration is NOT applied", plugin.Name, uuid)
-					found = true
-					break
-				}
-			}
-			if !found {
-				_, _, err := k.kongclient.DefaultApi.CreatePlugin(uuid, params)
-				if err != nil {
-					glog.Infof("Failed to create plugin for ing/%s/%s with annotation %s", ing.Namespace, ing.Name, a)
-					return err
-				}
-			}
+			k.recordApplySuccess(ReasonKongPluginApplied, "plugin", name, obj)
+			continue
+		}
+
+		desiredConfig, err := normalizePluginConfig(plugin.Config)
+		if err != nil {
+			k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", name, err, obj)
+			return err
+		}
+		currentConfig, err := normalizePluginConfig(current.Config)
+		if err != nil {
+			k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", name, err, obj)
+			return err
 		}
+		if reflect.DeepEqual(desiredConfig, currentConfig) {
+			glog.V(4).Infof("Plugin (%s) already up to date for API (%s)", name, uuid)
+			continue
+		}
+		if _, _, err := kongclient.DefaultApi.UpdatePlugin(uuid, current.Id, params); err != nil {
+			glog.Infof("Failed to update plugin %s for API (%s)", name, uuid)
+			k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", name, err, obj)
+			return err
+		}
+		k.recordApplySuccess(ReasonKongPluginApplied, "plugin", name, obj)
 	}
-	return err
+
+	for name, current := range existing {
+		if _, stillDesired := desired[name]; stillDesired {
+			continue
+		}
+		if err := kongclient.DefaultApi.DeletePlugin(uuid, current.Id); err != nil {
+			glog.Infof("Failed to remove stale plugin %s for API (%s)", name, uuid)
+			k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", name, err, obj)
+			return err
+		}
+		glog.Infof("Removed plugin %s from API (%s), no longer desired", name, uuid)
+	}
+
+	return nil
 }
 
-func (k *KongController) TryConfigureCertificates(ing *v1beta1.Ingress) error {
+func (k *KongController) TryConfigureCertificates(kongclient *kongswagger.APIClient, ing *v1beta1.Ingress) error {
 	for _, t := range ing.Spec.TLS {
 		for _, h := range t.Hosts {
 			secret, err := k.client.CoreV1().Secrets(ing.Namespace).Get(t.SecretName, metav1.GetOptions{})
 			if err != nil {
 				glog.Errorf("Failed to list secrets to match with Ingress TLS certificates")
+				k.recordApplyFailure(ReasonKongCertificateApplyFailed, "certificate", h, err, ing)
 				return err
 			}
 			if secret.Type != v1.SecretTypeTLS {
 				errmessage := fmt.Sprintf("Secret Specified for Ingress is not a TLS Secret (found %s instead)", secret.Type)
 				glog.Error(errmessage)
-				return errors.New(errmessage)
+				err := errors.New(errmessage)
+				k.recordApplyFailure(ReasonKongCertificateApplyFailed, "certificate", h, err, ing, secret)
+				return err
 			}
 
-			cert := kongswagger.Certificate{
-				Cert: string(secret.Data["tls.crt"]),
-				Key: string(secret.Data["tls.key"]),
-				Snis: []string{ h },
-			}
-			options := map[string]interface{} {
-				"certificate": cert,
-			}
-			_, _, err = k.kongclient.DefaultApi.CreateCertificate(options)
-			if err != nil {
-				glog.Errorf("Failed to create kong tls certificate for host %s in ingress %s/%s", h, ing.Namespace, ing.Name)
+			if err := k.reconcileCertificateForHost(kongclient, secret, h, ing); err != nil {
+				glog.Errorf("Failed to sync kong tls certificate for host %s in ingress %s/%s", h, ing.Namespace, ing.Name)
 				return err
 			}
 		}
@@ -333,87 +635,16 @@ func (k *KongController) TryConfigureCertificates(ing *v1beta1.Ingress) error {
 	return nil
 }
 
-func (k *KongController) TryAutoConfigureAuth0(ing *v1beta1.Ingress) error {
-	host := ""
-	for k, v := range ing.Annotations {
-		if k == jwtAuth0DomainAnnotation {
-			host = v
-			break
-		}
-	}
+// TryAutoConfigureAuth0 reconciles the Kong consumer + JWT credentials for
+// the Auth0 tenant named by the `kolihub.io/x-jwt-auth0-domain` annotation.
+// The actual JWKS-based reconciliation (with single-PEM fallback and a
+// periodic background refresh) lives in auth0.go.
+func (k *KongController) TryAutoConfigureAuth0(kongclient *kongswagger.APIClient, ing *v1beta1.Ingress) error {
+	host := ing.Annotations[jwtAuth0DomainAnnotation]
 	if host == "" {
 		return nil
 	}
-
-	cfg := auth0.DefaultTransportConfig().WithHost(host)
-	client := auth0.NewHTTPClientWithConfig(nil, cfg)
-
-	certBuf := bytes.NewBufferString("")
-	_, err := client.Operations.GetPEM(nil, certBuf)
-	if err != nil {
-		glog.Errorf("Failed to get x509 certificate from Auth0")
-		return err
-	}
-	block, _ := pem.Decode(certBuf.Bytes())
-	var cert *x509.Certificate
-	cert, err = x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		glog.Errorf("Failed to parse x509 certificate returned by Auth0")
-		return err
-	}
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
-	if err != nil {
-		glog.Errorf("Failed to marshal public key from Auth0's certificate")
-		return err
-	}
-	var pemkey = &pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: asn1Bytes,
-	}
-	buf := bytes.NewBufferString("")
-	err = pem.Encode(buf, pemkey)
-	if err != nil {
-		glog.Errorf("Failed to encode public key from Auth0's certificate")
-		return err
-	}
-
-	_, res, err := k.kongclient.DefaultApi.GetConsumer(host)
-	if err != nil {
-		if res.StatusCode == http.StatusNotFound {
-			consumer := kongswagger.Consumer{
-				Username: host,
-			}
-			_, err := k.kongclient.DefaultApi.CreateConsumer(consumer)
-			if err != nil {
-				glog.Errorf("Failed to create default JWT-associated Consumer for host (%s)", host)
-				return err
-			}
-		} else {
-			glog.Errorf("Failed to get consumer (%s) in Auth0 auto-configuration", host)
-			return err
-		}
-	}
-
-	list, _, err := k.kongclient.DefaultApi.ListJWTCredentials(host)
-	if err != nil {
-		glog.Errorf("Failed to list JWT credentials for default consumer (%s)", host)
-		return err
-	}
-	if list.Total == 0 {
-		jwtcred := kongswagger.JwtCredential{
-			Algorithm:    "RS256",
-			RsaPublicKey: buf.String(),
-			// iss field ends with a '/'
-			Key:          "https://" + host + "/",
-		}
-		_, _, err = k.kongclient.DefaultApi.CreateJWTCredential(host, jwtcred)
-		if err != nil {
-			glog.Errorf("Failed to create JWT credential for default consumer (%s)", host)
-			return err
-		}
-	}
-
-	return nil
+	return k.reconcileAuth0ForHost(kongclient, host, ing)
 }
 
 func (k *KongController) syncIngress(key string, numRequeues int) error {
@@ -428,6 +659,10 @@ func (k *KongController) syncIngress(key string, numRequeues int) error {
 	}
 
 	ing := obj.(*v1beta1.Ingress)
+	kongIngressOverrides, err := k.resolveKongIngressOverrides(ing)
+	if err != nil {
+		return err
+	}
 	if numRequeues > autoClaimMaxRetries {
 		// The dirty state is used only to indicate the object couldn't recover
 		// from a bad state, useful to warn clients.
@@ -454,6 +689,11 @@ func (k *KongController) syncIngress(key string, numRequeues int) error {
 	//}
 	glog.V(4).Infof("%s - Allowed to sync ingress routes, found all domains.", key)
 	// TODO: add tls
+	// ingressAPIs accumulates every path's desired kong.API across every rule
+	// of this Ingress, so the declarative backend can push one merged
+	// reload for the whole Ingress instead of one partial reload per path
+	// (which would replace Kong's entire config with just that one API).
+	var ingressAPIs []*kong.API
 	// Rules could have repeated domains, it will be redundant but it will work.
 	for _, r := range ing.Spec.Rules {
 		if r.HTTP == nil {
@@ -507,11 +747,6 @@ func (k *KongController) syncIngress(key string, numRequeues int) error {
 				pathURI = "/"
 			}
 			apiName := fmt.Sprintf("%s~%s~%s", r.Host, ing.Namespace, GenAdler32Hash(pathURI))
-			api, resp := k.kongcli.API().Get(apiName)
-			if resp.Error() != nil && !apierrors.IsNotFound(resp.Error()) {
-				k.recorder.Eventf(ing, v1.EventTypeWarning, "FailedAddRoute", "%s", resp)
-				return fmt.Errorf("failed listing api: %s", resp)
-			}
 
 			stripUri, err := strconv.ParseBool(ing.Annotations["ingress.kubernetes.io/strip-uri"])
 			if err != nil {
@@ -525,49 +760,175 @@ func (k *KongController) syncIngress(key string, numRequeues int) error {
 				glog.Infof("Failed to parse preserve-host annotation, setting it to the default value false")
 			}
 
-			apiBody := &kong.API{
-				Name:         apiName,
-				UpstreamURL:  upstreamURL,
-				StripUri:     stripUri,
-				PreserveHost: preserveHost,
+			// A referenced KongIngress overrides the defaults below;
+			// Resolved* falls back to its own legacy flat fields before
+			// finally keeping def: the annotation value for strip-uri and
+			// preserve-host, Kong's own default for the other fields.
+			retries := kongDefaultRetries
+			connectTimeout := kongDefaultConnectTimeout
+			writeTimeout := kongDefaultWriteTimeout
+			readTimeout := kongDefaultReadTimeout
+			hashOn := kongDefaultHashOn
+			var healthchecks *kong.Healthchecks
+			if kongIngressOverrides != nil {
+				stripUri = kongIngressOverrides.ResolvedStripPath(stripUri)
+				preserveHost = kongIngressOverrides.ResolvedPreserveHost(preserveHost)
+				retries = kongIngressOverrides.ResolvedRetries(retries)
+				connectTimeout = kongIngressOverrides.ResolvedConnectTimeout(connectTimeout)
+				writeTimeout = kongIngressOverrides.ResolvedWriteTimeout(writeTimeout)
+				readTimeout = kongIngressOverrides.ResolvedReadTimeout(readTimeout)
+				hashOn = kongIngressOverrides.ResolvedHashOn(hashOn)
+				if kongIngressOverrides.Upstream != nil {
+					healthchecks = convertHealthchecks(kongIngressOverrides.Upstream.Healthchecks)
+				}
+			}
+
+			// desiredAPI is the read-only, never-mutated rendering of this
+			// path's desired state: used as-is for the declarative backend
+			// and the post-sync Konnect mirror, and as the template the
+			// entity backend's per-instance apiBody below is built from.
+			desiredAPI := &kong.API{
+				Name:           apiName,
+				UpstreamURL:    upstreamURL,
+				StripUri:       stripUri,
+				PreserveHost:   preserveHost,
+				Retries:        retries,
+				ConnectTimeout: connectTimeout,
+				WriteTimeout:   writeTimeout,
+				ReadTimeout:    readTimeout,
+				HashOn:         hashOn,
+				Healthchecks:   healthchecks,
 			}
 			if r.Host != "" {
-				apiBody.Hosts = []string{r.Host}
+				desiredAPI.Hosts = []string{r.Host}
 			}
 			if p.Path != "" {
-				apiBody.URIs = []string{pathURI}
-			}
-			// It will trigger an update when providing the uuid,
-			// otherwise a new record will be created.
-			if api != nil {
-				apiBody.UID = api.UID
-				apiBody.CreatedAt = api.CreatedAt
-			}
-			api, resp = k.kongcli.API().UpdateOrCreate(apiBody)
-			if resp.Error() != nil && !apierrors.IsConflict(resp.Error()) {
-				return fmt.Errorf("failed adding api: %s", resp)
+				desiredAPI.URIs = []string{pathURI}
 			}
-			glog.Infof("%s - added route for %s[%s]", key, r.Host, api.UID)
+			ingressAPIs = append(ingressAPIs, desiredAPI)
 
-			// configure the API
-			err = k.ConfigurePluginsForAPI(api.UID, ing)
-			if err != nil {
-				return err
-			}
-			glog.Infof("%s - finished creating plugins for %s[%s]", key, r.Host, api.UID)
+			// Every admin API call below is issued through forEachAdmin so that,
+			// when an AdminPool is configured, the route/plugin/certificate state
+			// is reconciled against every healthy Kong instance rather than a
+			// single arbitrarily-chosen one. Each instance owns its own UID. This
+			// is the entity backend's EntityApply; the declarative backend
+			// ignores EntityApply entirely, so its reload is deferred to the
+			// single merged Sync call after this loop instead of running here
+			// once per path.
+			err = k.translatorBackend.Sync(context.Background(), translator.TargetConfig{
+				EntityApply: func(ctx context.Context) error {
+					return k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+						api, resp := kongcli.API().Get(apiName)
+						if resp.Error() != nil && !apierrors.IsNotFound(resp.Error()) {
+							k.recordApplyFailure(ReasonKongAPIApplyFailed, "api", apiName, resp.Error(), ing)
+							return fmt.Errorf("failed listing api: %s", resp)
+						}
 
-			err = k.TryConfigureCertificates(ing)
+						apiBody := &kong.API{
+							Name:           apiName,
+							UpstreamURL:    upstreamURL,
+							StripUri:       stripUri,
+							PreserveHost:   preserveHost,
+							Retries:        retries,
+							ConnectTimeout: connectTimeout,
+							WriteTimeout:   writeTimeout,
+							ReadTimeout:    readTimeout,
+							HashOn:         hashOn,
+							Healthchecks:   healthchecks,
+						}
+						if r.Host != "" {
+							apiBody.Hosts = []string{r.Host}
+						}
+						if p.Path != "" {
+							apiBody.URIs = []string{pathURI}
+						}
+						// It will trigger an update when providing the uuid,
+						// otherwise a new record will be created.
+						if api != nil {
+							apiBody.UID = api.UID
+							apiBody.CreatedAt = api.CreatedAt
+						}
+						api, resp = kongcli.API().UpdateOrCreate(apiBody)
+						if resp.Error() != nil && !apierrors.IsConflict(resp.Error()) {
+							k.recordApplyFailure(ReasonKongAPIApplyFailed, "api", apiName, resp.Error(), ing)
+							return fmt.Errorf("failed adding api: %s", resp)
+						}
+						k.recordApplySuccess(ReasonKongAPIApplied, "api", apiName, ing)
+						glog.Infof("%s - added route for %s[%s]", key, r.Host, api.UID)
+
+						if err := k.ConfigurePluginsForAPI(kongclient, api.UID, ing); err != nil {
+							return err
+						}
+						glog.Infof("%s - finished creating plugins for %s[%s]", key, r.Host, api.UID)
+
+						if err := k.TryConfigureCertificates(kongclient, ing); err != nil {
+							return err
+						}
+						glog.Infof("%s - finished creating certificates for %s[%s]", key, r.Host, api.UID)
+
+						if err := k.TryAutoConfigureAuth0(kongclient, ing); err != nil {
+							glog.Errorf("Failed to configure Auth0 for %s[%s]", r.Host, api.UID)
+							return err
+						}
+						return nil
+					})
+				},
+			})
 			if err != nil {
 				return err
 			}
-			glog.Infof("%s - finished creating certificates for %s[%s]", key, r.Host, api.UID)
 
-			err = k.TryAutoConfigureAuth0(ing)
-			if err != nil {
-				glog.Errorf("Failed to configure Auth0 for %s[%s]", r.Host, api.UID)
-				return err
+		}
+	}
+
+	// The declarative backend reloads Kong's *entire* config in one PUT, so
+	// pushing once per path (each with only that path's API) would replace
+	// every other Ingress's routes with just the last path synced. Instead,
+	// merge this Ingress's APIs into Kong's currently active declarative
+	// config and reload the merged snapshot exactly once, after every path
+	// has been processed. The entity backend ignores this; its per-path
+	// EntityApply above already applied each API individually.
+	var declarativeSnapshot []byte
+	if k.cfg.SyncBackend == "declarative" {
+		snapshot, err := k.syncDeclarativeAPIs(ingressAPIs)
+		if err != nil {
+			return fmt.Errorf("failed reloading declarative config: %s", err)
+		}
+		declarativeSnapshot = snapshot
+	}
+
+	// clusterAPIs remembers this Ingress's desired APIs (or forgets it
+	// entirely, once it has none) so the entity-backend Konnect payload
+	// below can be assembled from every Ingress's last-synced APIs instead
+	// of just this one's.
+	nsName := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+	if len(ingressAPIs) > 0 {
+		k.clusterAPIs.set(nsName, ingressAPIs)
+	} else {
+		k.clusterAPIs.drop(nsName)
+	}
+
+	// Konnect expects the reconciled config for the whole cluster (Konnect's
+	// own docs call it "the reconciled dc/deck config"), not a per-Ingress
+	// fragment, so mirror once per Ingress instead of once per path. When the
+	// declarative backend is in use, the snapshot it just reloaded already
+	// covers every other Ingress too and is reused as-is; the entity backend
+	// has no such whole-cluster snapshot to fetch (EntityBackend.Fetch always
+	// returns nil), so it mirrors clusterAPIs.merged() instead, which covers
+	// every Ingress the controller has synced rather than just this one.
+	if k.konnectClient != nil {
+		payload := declarativeSnapshot
+		if payload == nil {
+			var merr error
+			payload, merr = json.Marshal(declarativeConfig{APIs: k.clusterAPIs.merged()})
+			if merr != nil {
+				glog.Errorf("konnect: failed marshaling config for ing/%s/%s: %s", ing.Namespace, ing.Name, merr)
+				payload = nil
 			}
 		}
+		if payload != nil {
+			k.mirrorToKonnect(ing, payload)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,320 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/apis/kong/v1alpha1"
+	"github.com/koli/kong-ingress/pkg/kong"
+	"gopkg.in/square/go-jose.v2/json"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// pluginRefAnnotation names KongPlugin objects, in the Ingress's own
+// namespace, to attach to every API the Ingress generates. It is resolved in
+// addition to (not instead of) the legacy `kolihub.io/plugin-*` annotations,
+// so existing annotation-based Ingresses keep working unmodified.
+const pluginRefAnnotation = "kolihub.io/plugin-refs"
+
+// kongIngressRefAnnotation names a KongIngress, in the Ingress's own
+// namespace, carrying Route/Service/Upstream overrides for every API the
+// Ingress generates, the same way `kubernetes.io/ingress.class` selects an
+// Ingress controller.
+const kongIngressRefAnnotation = "kolihub.io/kong-ingress"
+
+func newKongRouteInformer(k *KongController, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	inf := cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(k.extClient, "kongroutes", metav1.NamespaceAll, fields.Everything()),
+		&v1alpha1.KongRoute{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { k.routeQueue.Add(obj) },
+		DeleteFunc: func(obj interface{}) { k.routeQueue.Add(obj) },
+		UpdateFunc: func(o, n interface{}) {
+			old := o.(*v1alpha1.KongRoute)
+			new := n.(*v1alpha1.KongRoute)
+			if old.ResourceVersion != new.ResourceVersion {
+				k.routeQueue.Add(n)
+			}
+		},
+	})
+	return inf
+}
+
+func newKongPluginInformer(k *KongController, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	inf := cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(k.extClient, "kongplugins", metav1.NamespaceAll, fields.Everything()),
+		&v1alpha1.KongPlugin{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	// KongPlugin objects don't drive their own apply: they're resolved by name
+	// from the Ingress/KongRoute that references them, and a change takes
+	// effect on the referencing object's own resync. Changes are enqueued on
+	// pluginQueue only for syncKongPlugin's validation; the referencing
+	// Ingress/KongRoute is not re-enqueued.
+	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { k.pluginQueue.Add(obj) },
+		UpdateFunc: func(o, n interface{}) {
+			old := o.(*v1alpha1.KongPlugin)
+			new := n.(*v1alpha1.KongPlugin)
+			if old.ResourceVersion != new.ResourceVersion {
+				k.pluginQueue.Add(n)
+			}
+		},
+	})
+	return inf
+}
+
+// newKongIngressInformer watches KongIngress objects. Like KongPlugin,
+// KongIngress doesn't drive its own sync: it's resolved by name from the
+// Ingress that references it via kongIngressRefAnnotation, and a change
+// takes effect on the referencing Ingress's own resync.
+func newKongIngressInformer(k *KongController, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(k.extClient, "kongingresses", metav1.NamespaceAll, fields.Everything()),
+		&v1alpha1.KongIngress{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// resolveKongIngressOverrides looks up the KongIngress named by ing's
+// kongIngressRefAnnotation, in ing's own namespace, returning nil (not an
+// error) when the Ingress doesn't reference one so callers can apply
+// defaults unconditionally.
+func (k *KongController) resolveKongIngressOverrides(ing *v1beta1.Ingress) (*v1alpha1.KongIngressSpec, error) {
+	name := ing.Annotations[kongIngressRefAnnotation]
+	if name == "" {
+		return nil, nil
+	}
+	obj, exists, err := k.infKongIngress.GetIndexer().GetByKey(ing.Namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		err := fmt.Errorf("KongIngress %s/%s referenced by ing/%s/%s not found", ing.Namespace, name, ing.Namespace, ing.Name)
+		k.recordApplyFailure(ReasonKongAPIApplyFailed, "kongingress", name, err, ing)
+		return nil, err
+	}
+	return &obj.(*v1alpha1.KongIngress).Spec, nil
+}
+
+// convertHealthchecks renders a KongIngressHealthcheck override into the
+// kong.Healthchecks shape it mirrors, field for field. Returns nil for a nil
+// input so callers can assign the result unconditionally.
+func convertHealthchecks(hc *v1alpha1.KongIngressHealthcheck) *kong.Healthchecks {
+	if hc == nil {
+		return nil
+	}
+	out := &kong.Healthchecks{}
+	if hc.Active != nil {
+		out.Active = &kong.ActiveHealthcheck{
+			HTTPPath: hc.Active.HTTPPath,
+			Timeout:  hc.Active.Timeout,
+		}
+		if hc.Active.Unhealthy != nil {
+			out.Active.Unhealthy = convertHealthThreshold(hc.Active.Unhealthy)
+		}
+	}
+	if hc.Passive != nil {
+		out.Passive = &kong.PassiveHealthcheck{}
+		if hc.Passive.Unhealthy != nil {
+			out.Passive.Unhealthy = convertHealthThreshold(hc.Passive.Unhealthy)
+		}
+	}
+	return out
+}
+
+// convertHealthThreshold renders a KongIngressHealthThreshold override into
+// the kong.HealthThreshold shape it mirrors, field for field.
+func convertHealthThreshold(t *v1alpha1.KongIngressHealthThreshold) *kong.HealthThreshold {
+	return &kong.HealthThreshold{
+		HTTPFailures: t.HTTPFailures,
+		TCPFailures:  t.TCPFailures,
+		Successes:    t.Successes,
+	}
+}
+
+// resolvePluginRefs looks up every KongPlugin named in the Ingress's
+// `kolihub.io/plugin-refs` annotation (comma-separated) and translates each
+// into a kongswagger.Plugin, keyed by Kong plugin name so it merges cleanly
+// with the annotation-parsed set in ConfigurePluginsForAPI.
+func (k *KongController) resolvePluginRefs(ing *v1beta1.Ingress) (map[string]kongswagger.Plugin, error) {
+	raw, ok := ing.Annotations[pluginRefAnnotation]
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	plugins := make(map[string]kongswagger.Plugin)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		obj, exists, err := k.infPlugin.GetIndexer().GetByKey(ing.Namespace + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			err := fmt.Errorf("KongPlugin %s/%s referenced by ing/%s/%s not found", ing.Namespace, name, ing.Namespace, ing.Name)
+			k.recordApplyFailure(ReasonKongPluginApplyFailed, "plugin", name, err, ing)
+			return nil, err
+		}
+		kp := obj.(*v1alpha1.KongPlugin)
+		var iplugin interface{} = kp.Spec.Config
+		plugins[kp.Spec.PluginName] = kongswagger.Plugin{
+			Name:   kp.Spec.PluginName,
+			Config: &iplugin,
+		}
+	}
+	return plugins, nil
+}
+
+// configurePluginsForRoute reconciles the plugins attached to a Kong API
+// with a KongRoute's PluginRefs, the route equivalent of
+// ConfigurePluginsForAPI's annotation-driven reconciliation: plugins no
+// longer referenced are deleted and drifted config is patched instead of
+// unconditionally creating every referenced plugin on every resync.
+func (k *KongController) configurePluginsForRoute(kongclient *kongswagger.APIClient, uuid string, route *v1alpha1.KongRoute) error {
+	desired := make(map[string]kongswagger.Plugin)
+	for _, pluginName := range route.Spec.PluginRefs {
+		pluginObj, exists, err := k.infPlugin.GetIndexer().GetByKey(route.Namespace + "/" + pluginName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("KongPlugin %s/%s not found", route.Namespace, pluginName)
+		}
+		kp := pluginObj.(*v1alpha1.KongPlugin)
+		var iplugin interface{} = kp.Spec.Config
+		desired[kp.Spec.PluginName] = kongswagger.Plugin{Name: kp.Spec.PluginName, Config: &iplugin}
+	}
+	return k.reconcilePlugins(kongclient, uuid, desired, route)
+}
+
+// kongAPIFromRoute translates a KongRoute's spec into the same kong.API shape
+// syncIngress builds from an Ingress rule, so both paths share Kong's
+// UpdateOrCreate semantics.
+func kongAPIFromRoute(route *v1alpha1.KongRoute, upstreamURL string) *kong.API {
+	api := &kong.API{
+		Name:         fmt.Sprintf("kongroute~%s~%s", route.Namespace, route.Name),
+		UpstreamURL:  upstreamURL,
+		Hosts:        route.Spec.Hosts,
+		URIs:         route.Spec.Paths,
+		StripUri:     route.Spec.StripURI,
+		PreserveHost: route.Spec.PreserveHost,
+	}
+	return api
+}
+
+// syncRoute reconciles a single KongRoute, the CRD-based alternative to
+// expressing routing through an Ingress plus `kolihub.io/plugin-*`
+// annotations. It mirrors syncIngress's API-then-plugins flow but resolves
+// plugins exclusively by KongPlugin name reference.
+func (k *KongController) syncRoute(key string, numRequeues int) error {
+	obj, exists, err := k.infRoute.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		glog.V(4).Infof("%s - kongroute doesn't exist", key)
+		return nil
+	}
+	route := obj.(*v1alpha1.KongRoute)
+
+	if err := route.Spec.Validate(); err != nil {
+		glog.Errorf("%s - kongroute %s/%s rejected: %s", key, route.Namespace, route.Name, err)
+		return k.setRouteStatus(route, "", err.Error())
+	}
+
+	if _, err := k.client.Core().Services(route.Namespace).Get(route.Spec.ServiceName, metav1.GetOptions{}); err != nil {
+		return k.setRouteStatus(route, "", fmt.Sprintf("service %s not found: %s", route.Spec.ServiceName, err))
+	}
+
+	proto := "http"
+	if route.Spec.ServicePort == 443 {
+		proto = "https"
+	}
+	upstreamURL := k.getUpstream(proto, route.Namespace, route.Spec.ServiceName, route.Spec.ServicePort)
+
+	// forEachAdmin fans this out to every admin instance concurrently when an
+	// AdminPool is configured (see AdminPool.ForEach), and each instance owns
+	// its own UID the same way syncIngress's EntityApply does. lastID is
+	// therefore guarded by idMu rather than written to directly, and only
+	// ever used for status reporting: it reflects whichever instance
+	// happened to apply last, not a single canonical Kong ID.
+	var idMu sync.Mutex
+	var lastID string
+	err = k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+		apiBody := kongAPIFromRoute(route, upstreamURL)
+		existing, resp := kongcli.API().Get(apiBody.Name)
+		if resp.Error() != nil && !apierrors.IsNotFound(resp.Error()) {
+			return resp.Error()
+		}
+		if existing != nil {
+			apiBody.UID = existing.UID
+			apiBody.CreatedAt = existing.CreatedAt
+		}
+		api, resp := kongcli.API().UpdateOrCreate(apiBody)
+		if resp.Error() != nil && !apierrors.IsConflict(resp.Error()) {
+			return resp.Error()
+		}
+		idMu.Lock()
+		lastID = api.UID
+		idMu.Unlock()
+
+		return k.configurePluginsForRoute(kongclient, api.UID, route)
+	})
+	if err != nil {
+		return k.setRouteStatus(route, "", err.Error())
+	}
+	return k.setRouteStatus(route, lastID, "")
+}
+
+// setRouteStatus patches a KongRoute's status subresource with the Kong
+// entity UUID and the last apply error (empty on success), so `kubectl get
+// kongroute` shows drift without needing controller logs.
+func (k *KongController) setRouteStatus(route *v1alpha1.KongRoute, kongID, lastErr string) error {
+	status := v1alpha1.KongRouteStatus{KongID: kongID, LastAppliedError: lastErr}
+	payload, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+	_, err = k.extClient.Patch(types.MergePatchType).
+		Resource("kongroutes").
+		Namespace(route.Namespace).
+		Name(route.Name).
+		SubResource("status").
+		Body(payload).
+		DoRaw()
+	return err
+}
+
+// syncKongPlugin currently only validates that the plugin's config is
+// sensible; the actual apply happens when an Ingress or KongRoute resolves a
+// reference to it via resolvePluginRefs/Spec.PluginRefs.
+func (k *KongController) syncKongPlugin(key string, numRequeues int) error {
+	obj, exists, err := k.infPlugin.GetStore().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	kp := obj.(*v1alpha1.KongPlugin)
+	if kp.Spec.PluginName == "" {
+		return fmt.Errorf("KongPlugin %s/%s is missing spec.pluginName", kp.Namespace, kp.Name)
+	}
+	return nil
+}
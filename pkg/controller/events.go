@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Event reasons emitted while applying Kong configuration. kubectl describe
+// on the Ingress (and, where relevant, the referenced Service/Secret) should
+// show these without anyone needing to tail the controller logs.
+const (
+	ReasonKongAPIApplyFailed         = "KongAPIApplyFailed"
+	ReasonKongAPIApplied             = "KongAPIApplied"
+	ReasonKongPluginApplyFailed      = "KongPluginApplyFailed"
+	ReasonKongPluginApplied          = "KongPluginApplied"
+	ReasonKongCertificateApplyFailed = "KongCertificateApplyFailed"
+	ReasonKongCertificateApplied     = "KongCertificateApplied"
+	ReasonAuth0DiscoveryFailed       = "Auth0DiscoveryFailed"
+	ReasonAuth0Configured            = "Auth0Configured"
+	ReasonKonnectSyncFailed          = "KonnectSyncFailed"
+)
+
+// kongAPIError is implemented by the generated kongswagger error types and
+// lets us surface Kong's parsed error body instead of just the HTTP status.
+type kongAPIError interface {
+	Body() []byte
+}
+
+// kongErrorDetail extracts the response body from a Kong admin API error, if
+// any, falling back to err.Error().
+func kongErrorDetail(err error) string {
+	if err == nil {
+		return ""
+	}
+	if apiErr, ok := err.(kongAPIError); ok {
+		if body := apiErr.Body(); len(body) > 0 {
+			return string(body)
+		}
+	}
+	return err.Error()
+}
+
+// recordApplyFailure emits a correlated Warning event on every object passed
+// in (typically the Ingress and the Service/Secret the failing entity was
+// derived from) so a bad plugin annotation, invalid TLS Secret, or rejected
+// API body is visible without tailing controller logs.
+func (k *KongController) recordApplyFailure(reason, entityKind, entityName string, err error, objs ...runtime.Object) {
+	msg := fmt.Sprintf("failed to apply kong %s %q: %s", entityKind, entityName, kongErrorDetail(err))
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		k.recorder.Eventf(obj, v1.EventTypeWarning, reason, "%s", msg)
+	}
+}
+
+// recordApplySuccess emits a matching Normal event once a previously failing
+// (or first-time) apply succeeds, so recovery is visible the same way the
+// failure was.
+func (k *KongController) recordApplySuccess(reason, entityKind, entityName string, objs ...runtime.Object) {
+	msg := fmt.Sprintf("kong %s %q applied successfully", entityKind, entityName)
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		k.recorder.Eventf(obj, v1.EventTypeNormal, reason, "%s", msg)
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/koli/kong-ingress/pkg/konnect"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+)
+
+// konnectQueueSize bounds how many pending config mirrors the controller
+// will buffer before dropping new ones; a backlog this size means Konnect
+// has been unreachable for a while and holding more stale payloads in
+// memory doesn't help.
+const konnectQueueSize = 256
+
+// konnectMaxAttempts is how many attempts, the first one included,
+// syncToKonnectWithRetry makes at a single mirror request before giving up
+// and surfacing an event.
+const konnectMaxAttempts = 5
+
+// konnectSyncRequest is a single mirror attempt, retried independently of
+// the local admin push that triggered it.
+type konnectSyncRequest struct {
+	ing    *v1beta1.Ingress
+	config []byte
+}
+
+// newKonnectClient builds the Konnect client the controller mirrors local
+// Kong admin pushes to, or nil when Konnect mirroring isn't enabled or the
+// client can't be built (e.g. a bad certificate path), in which case the
+// controller keeps syncing Kong locally without Konnect.
+func newKonnectClient(cfg *Config) *konnect.Client {
+	if !cfg.KonnectEnabled {
+		return nil
+	}
+	client, err := konnect.NewClient(cfg.KonnectAddress, cfg.KonnectRuntimeGroupID, cfg.KonnectTLS)
+	if err != nil {
+		glog.Errorf("konnect: disabling mirror, failed building client: %s", err)
+		return nil
+	}
+	return client
+}
+
+// runKonnectMirror drains k.konnectQueue and uploads each request to
+// Konnect, retrying with backoff on failure so a Konnect outage never
+// blocks (or is blocked by) the local Kong admin sync loop.
+func (k *KongController) runKonnectMirror(stopc <-chan struct{}) {
+	for {
+		select {
+		case req := <-k.konnectQueue:
+			k.syncToKonnectWithRetry(req)
+		case <-stopc:
+			return
+		}
+	}
+}
+
+// syncToKonnectWithRetry uploads req with exponential backoff, giving up
+// (and recording a failure event) after konnectMaxAttempts.
+func (k *KongController) syncToKonnectWithRetry(req konnectSyncRequest) {
+	backoff := time.Second
+	for attempt := 1; attempt <= konnectMaxAttempts; attempt++ {
+		err := k.konnectClient.SyncConfig(req.config)
+		if err == nil {
+			return
+		}
+		if attempt == konnectMaxAttempts {
+			glog.Errorf("konnect: giving up mirroring config for ing/%s/%s after %d attempts: %s", req.ing.Namespace, req.ing.Name, attempt, err)
+			k.recordApplyFailure(ReasonKonnectSyncFailed, "konnect-config", req.ing.Name, err, req.ing)
+			return
+		}
+		glog.Warningf("konnect: retrying config mirror for ing/%s/%s (attempt %d/%d): %s", req.ing.Namespace, req.ing.Name, attempt, konnectMaxAttempts, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// mirrorToKonnect enqueues a best-effort, non-blocking request to mirror
+// config to Konnect once the local Kong admin sync that produced it already
+// succeeded. It's a no-op when Konnect mirroring isn't enabled, and drops
+// the request (with a log line) if the mirror queue is full.
+func (k *KongController) mirrorToKonnect(ing *v1beta1.Ingress, config []byte) {
+	if k.konnectClient == nil {
+		return
+	}
+	select {
+	case k.konnectQueue <- konnectSyncRequest{ing: ing, config: config}:
+	default:
+		glog.Warningf("konnect: mirror queue full, dropping config sync for ing/%s/%s", ing.Namespace, ing.Name)
+	}
+}
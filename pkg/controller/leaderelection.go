@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// newSyncLeaderLock builds the resourcelock contested to decide which
+// controller replica is allowed to write Kong configuration when
+// cfg.LeaderElect is set. It uses its own lease, separate from
+// newWebhookConfigReconciler's, so the two elections (Kong admin sync vs.
+// webhook-config caBundle) can have different leaders.
+func newSyncLeaderLock(k *KongController, cfg *Config) resourcelock.Interface {
+	return &resourcelock.ConfigMapLock{
+		ConfigMapMeta: metav1.ObjectMeta{
+			Namespace: cfg.PodNamespace,
+			Name:      "kong-ingress-sync-leader",
+		},
+		Client: k.client.Core(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: leaderElectionIdentity(),
+		},
+	}
+}
+
+// runLeaderElectedSync contests leadership via lock and calls startSyncing
+// only while holding it, passing it a stop channel that's closed the moment
+// leadership is lost, so a replica that stops being leader also stops its
+// Kong admin writers instead of racing whichever replica is elected next.
+// Blocks until stopc is closed.
+func runLeaderElectedSync(lock resourcelock.Interface, startSyncing func(stopc <-chan struct{}), stopc <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopc
+		cancel()
+	}()
+
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				glog.Infof("controller: acquired Kong sync leadership, starting admin writers")
+				startSyncing(leaderCtx.Done())
+				<-leaderCtx.Done()
+			},
+			OnStoppedLeading: func() {
+				glog.Infof("controller: lost Kong sync leadership, stopping admin writers")
+			},
+		},
+	})
+}
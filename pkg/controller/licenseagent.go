@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"github.com/koli/kong-ingress/pkg/kong"
+	"github.com/koli/kong-ingress/pkg/licenseagent"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newLicenseAgent builds the controller's license agent, or nil when neither
+// LicenseSecretRef nor LicenseSource is configured. LicenseSecretRef takes
+// precedence, matching the admin-pool precedence convention of the more
+// specific/explicit option winning. k itself is used as the agent's
+// Injector, since injection just reuses forEachAdmin's existing pool
+// fan-out/quorum.
+func newLicenseAgent(k *KongController, cfg *Config) *licenseagent.Agent {
+	if cfg.LicenseSecretRef != "" {
+		return licenseagent.NewAgent("", k.fetchLicenseSecret, k)
+	}
+	if cfg.LicenseSource != "" {
+		return licenseagent.NewAgent(cfg.LicenseSource, nil, k)
+	}
+	return nil
+}
+
+// InjectLicense implements licenseagent.Injector by pushing payload to every
+// configured Kong Admin API instance via forEachAdmin, so license injection
+// gets the same pool fan-out and quorum semantics as every other write.
+func (k *KongController) InjectLicense(payload []byte) error {
+	return k.forEachAdmin(func(kongcli *kong.CoreClient, kongclient *kongswagger.APIClient) error {
+		return ensureLicense(kongclient, payload)
+	})
+}
+
+// ensureLicense creates or updates Kong's license object, skipping the write
+// entirely when the stored payload already matches the desired one.
+func ensureLicense(kongclient *kongswagger.APIClient, payload []byte) error {
+	list, _, err := kongclient.DefaultApi.ListLicenses(nil)
+	if err != nil {
+		return fmt.Errorf("failed listing licenses: %s", err)
+	}
+	desired := string(payload)
+	options := map[string]interface{}{"license": kongswagger.License{Payload: desired}}
+
+	if len(list.Data) == 0 {
+		if _, _, err := kongclient.DefaultApi.CreateLicense(options); err != nil {
+			return fmt.Errorf("failed creating license: %s", err)
+		}
+		return nil
+	}
+	existing := list.Data[0]
+	if existing.Payload == desired {
+		return nil
+	}
+	if _, _, err := kongclient.DefaultApi.UpdateLicense(existing.Id, options); err != nil {
+		return fmt.Errorf("failed updating license: %s", err)
+	}
+	return nil
+}
+
+// splitSecretRef splits a "namespace/name" LicenseSecretRef. A bare name
+// with no slash comes back with an empty namespace, which
+// fetchLicenseSecret rejects rather than guessing at, since defaulting to
+// the controller's own
+// namespace would silently watch the wrong Secret if that assumption is
+// ever wrong.
+func splitSecretRef(ref string) (namespace, name string) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 {
+		return "", ref
+	}
+	return parts[0], parts[1]
+}
+
+// fetchLicenseSecret reads the license payload out of the "license" key of
+// the Secret named by LicenseSecretRef.
+func (k *KongController) fetchLicenseSecret() ([]byte, error) {
+	ns, name := splitSecretRef(k.cfg.LicenseSecretRef)
+	if ns == "" {
+		return nil, fmt.Errorf("license secret ref %q is not of the form namespace/name", k.cfg.LicenseSecretRef)
+	}
+	secret, err := k.client.Core().Secrets(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	payload, ok := secret.Data["license"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no %q key", ns, name, "license")
+	}
+	return payload, nil
+}
+
+// newLicenseSecretInformer watches only the single Secret named by
+// LicenseSecretRef, triggering an immediate license refresh on every change
+// so a rotated license doesn't have to wait for the next poll interval.
+func newLicenseSecretInformer(k *KongController, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	ns, name := splitSecretRef(k.cfg.LicenseSecretRef)
+	inf := cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(
+			k.client.Core().RESTClient(), "secrets", ns,
+			fields.OneTermEqualSelector("metadata.name", name),
+		),
+		&v1.Secret{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { k.licenseAgent.Refresh() },
+		UpdateFunc: func(o, n interface{}) { k.licenseAgent.Refresh() },
+	})
+	return inf
+}
+
+// LicenseHealthz reports the license agent's last known status, mirroring
+// AdminPool.ServeHTTP's plain-text /healthz handler style, so an unlicensed
+// cluster is visible to readiness probes/ops without tailing logs.
+func (k *KongController) LicenseHealthz(w http.ResponseWriter, r *http.Request) {
+	if k.licenseAgent == nil {
+		fmt.Fprintf(w, "license agent disabled\n")
+		return
+	}
+	status := k.licenseAgent.Status()
+	if !status.Licensed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "unlicensed: %s\n", status.LastError)
+		return
+	}
+	fmt.Fprintf(w, "licensed (source=%s, last fetched %s)\n", status.LastSource, status.LastFetchedAt.Format(time.RFC3339))
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"sync"
+
+	kongswagger "github.com/jgensler8/kong-swagger/generated"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pluginParseCacheKey identifies a single parsed plugin annotation. Keying
+// on the Ingress's UID/resourceVersion means a resync that doesn't change
+// the Ingress object doesn't force us to re-unmarshal every plugin
+// annotation on every pass.
+type pluginParseCacheKey struct {
+	ingUID          types.UID
+	annotation      string
+	resourceVersion string
+}
+
+type pluginParseCacheEntry struct {
+	plugin kongswagger.Plugin
+	err    error
+}
+
+// pluginParseCache memoizes the result of parsing a `kolihub.io/plugin-*`
+// annotation into a kongswagger.Plugin, so syncIngress resyncs only pay the
+// unmarshal cost once per (ingress, annotation, resourceVersion) tuple.
+type pluginParseCache struct {
+	mu      sync.Mutex
+	entries map[pluginParseCacheKey]pluginParseCacheEntry
+}
+
+func newPluginParseCache() *pluginParseCache {
+	return &pluginParseCache{entries: make(map[pluginParseCacheKey]pluginParseCacheEntry)}
+}
+
+func (c *pluginParseCache) get(key pluginParseCacheKey) (pluginParseCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *pluginParseCache) set(key pluginParseCacheKey, entry pluginParseCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Stale resourceVersions for a still-existing Ingress are dropped
+	// lazily: since the key includes the resourceVersion, entries for
+	// superseded versions are simply never looked up again. They're
+	// harmless to leave around until the Ingress itself is deleted, at
+	// which point the Ingress informer's DeleteFunc calls dropAll to evict
+	// them for good.
+	c.entries[key] = entry
+}
+
+func (c *pluginParseCache) dropAll(ingUID types.UID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.ingUID == ingUID {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/koli/kong-ingress/pkg/kong"
+	"github.com/koli/kong-ingress/pkg/translator"
+	"gopkg.in/square/go-jose.v2/json"
+)
+
+// newTranslatorBackend builds the translator.Backend selected by
+// cfg.SyncBackend: "entity" (the default, and the controller's original
+// behavior) or "declarative" for a deck-style diff-then-reload.
+func newTranslatorBackend(k *KongController, cfg *Config) translator.Backend {
+	if cfg.SyncBackend != "declarative" {
+		return translator.NewEntityBackend()
+	}
+	return translator.NewDeclarativeBackend(k.fetchDeclarativeConfig, k.applyDeclarativeConfig)
+}
+
+// declarativeAdminAddr returns a single Kong Admin API address to target for
+// declarative config fetch/reload. Unlike entity writes (fanned out via
+// forEachAdmin to every pool member), a declarative reload is a
+// whole-cluster operation against one Kong node's view, so only one address
+// is needed.
+func (k *KongController) declarativeAdminAddr() (string, error) {
+	if k.adminPool != nil {
+		addrs := k.adminPool.Addrs()
+		if len(addrs) == 0 {
+			return "", fmt.Errorf("translator: no healthy admin endpoints available")
+		}
+		return addrs[0], nil
+	}
+	if k.cfg.KongAdminHost != "" {
+		return k.cfg.KongAdminHost, nil
+	}
+	return "", fmt.Errorf("translator: no Kong admin endpoint configured")
+}
+
+// fetchDeclarativeConfig retrieves Kong's currently active declarative
+// config from /config, for DeclarativeBackend.Fetch.
+func (k *KongController) fetchDeclarativeConfig(ctx context.Context) ([]byte, error) {
+	addr, err := k.declarativeAdminAddr()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Get(fmt.Sprintf("http://%s/config", addr))
+	if err != nil {
+		return nil, fmt.Errorf("translator: failed fetching current config: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("translator: fetching current config returned status %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// declarativeConfig is the /config payload shape both fetchDeclarativeConfig
+// and syncDeclarativeAPIs read/write.
+type declarativeConfig struct {
+	APIs []*kong.API `json:"apis"`
+}
+
+// syncDeclarativeAPIs merges apis into Kong's currently active declarative
+// config (keyed by API name, so unrelated Ingresses' entries are preserved)
+// and pushes the merged result as a single reload. Doing this once per
+// Ingress, rather than once per path with only that path's API, is what
+// keeps a declarative reload from replacing the whole cluster's config with
+// a single Ingress path's worth of it.
+// syncDeclarativeAPIs merges apis into Kong's currently active declarative
+// config (keyed by API name, so unrelated Ingresses' entries are preserved),
+// pushes the merged result as a single reload, and returns the merged
+// payload so callers (e.g. the Konnect mirror) can reuse it as the full
+// reconciled config instead of recomputing it.
+func (k *KongController) syncDeclarativeAPIs(apis []*kong.API) ([]byte, error) {
+	if len(apis) == 0 {
+		return nil, nil
+	}
+	ctx := context.Background()
+	current, err := k.translatorBackend.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching current declarative config: %s", err)
+	}
+	var snapshot declarativeConfig
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed decoding current declarative config: %s", err)
+		}
+	}
+	merged := make(map[string]*kong.API, len(snapshot.APIs)+len(apis))
+	for _, api := range snapshot.APIs {
+		merged[api.Name] = api
+	}
+	for _, api := range apis {
+		merged[api.Name] = api
+	}
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	// Sorted so the merged payload is byte-stable across syncs that didn't
+	// actually change anything, matching up with DeclarativeBackend.Sync's
+	// bytes.Equal diff check instead of triggering a reload on every pass.
+	sort.Strings(names)
+	mergedAPIs := make([]*kong.API, len(names))
+	for i, name := range names {
+		mergedAPIs[i] = merged[name]
+	}
+	payload, err := json.Marshal(declarativeConfig{APIs: mergedAPIs})
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering declarative config: %s", err)
+	}
+	if err := k.translatorBackend.Sync(ctx, translator.TargetConfig{Declarative: payload}); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// applyDeclarativeConfig PUTs a full declarative config snapshot to Kong's
+// /config endpoint, for DeclarativeBackend.Sync.
+func (k *KongController) applyDeclarativeConfig(ctx context.Context, target []byte) error {
+	addr, err := k.declarativeAdminAddr()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("http://%s/config", addr), bytes.NewReader(target))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("translator: failed reloading config: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("translator: config reload rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/koli/kong-ingress/pkg/admission"
+	"github.com/koli/kong-ingress/pkg/apis/kong/v1alpha1"
+	"gopkg.in/square/go-jose.v2/json"
+	"k8s.io/api/core/v1"
+	v1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// newWebhookCertWatcher builds the admission webhook's CertWatcher, or nil
+// when cfg.WebhookBindAddress isn't set. WebhookCertSecret takes precedence
+// over WebhookCertPath/WebhookKeyPath, the same precedence LicenseSecretRef
+// has over LicenseSource.
+func newWebhookCertWatcher(k *KongController, cfg *Config) *admission.CertWatcher {
+	if cfg.WebhookBindAddress == "" {
+		return nil
+	}
+	if cfg.WebhookCertSecret != "" {
+		return admission.NewSecretCertWatcher(k.fetchWebhookCertSecret)
+	}
+	return admission.NewFileCertWatcher(cfg.WebhookCertPath, cfg.WebhookKeyPath)
+}
+
+// fetchWebhookCertSecret reads the "tls.crt"/"tls.key" keys out of the
+// Secret named by WebhookCertSecret, the same key convention
+// reconcileCertificateForHost uses for Ingress TLS secrets.
+func (k *KongController) fetchWebhookCertSecret() (certPEM, keyPEM []byte, err error) {
+	ns, name := splitSecretRef(k.cfg.WebhookCertSecret)
+	secret, err := k.client.Core().Secrets(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM, ok := secret.Data["tls.crt"]
+	if !ok {
+		return nil, nil, fmt.Errorf("secret %s/%s has no %q key", ns, name, "tls.crt")
+	}
+	keyPEM, ok = secret.Data["tls.key"]
+	if !ok {
+		return nil, nil, fmt.Errorf("secret %s/%s has no %q key", ns, name, "tls.key")
+	}
+	return certPEM, keyPEM, nil
+}
+
+// newWebhookCertSecretInformer watches only the single Secret named by
+// WebhookCertSecret, triggering an immediate certificate reload on every
+// change, mirroring newLicenseSecretInformer.
+func newWebhookCertSecretInformer(k *KongController, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	ns, name := splitSecretRef(k.cfg.WebhookCertSecret)
+	inf := cache.NewSharedIndexInformer(
+		cache.NewListWatchFromClient(
+			k.client.Core().RESTClient(), "secrets", ns,
+			fields.OneTermEqualSelector("metadata.name", name),
+		),
+		&v1.Secret{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if err := k.webhookCerts.Reload(); err != nil {
+				glog.Errorf("admission: failed reloading webhook certificate from secret: %s", err)
+			}
+		},
+		UpdateFunc: func(o, n interface{}) {
+			if err := k.webhookCerts.Reload(); err != nil {
+				glog.Errorf("admission: failed reloading webhook certificate from secret: %s", err)
+			}
+		},
+	})
+	return inf
+}
+
+// ValidateKongIngress implements admission.Validator by rejecting a
+// KongIngress that doesn't even decode; KongIngressSpec itself has no
+// further constraints to enforce yet beyond what the apiserver's own schema
+// validation already covers.
+func (k *KongController) ValidateKongIngress(raw []byte) error {
+	kongIngress := &v1alpha1.KongIngress{}
+	if err := json.Unmarshal(raw, kongIngress); err != nil {
+		return fmt.Errorf("failed decoding kongingress: %s", err)
+	}
+	return nil
+}
+
+// ValidateKongPlugin implements admission.Validator, rejecting a KongPlugin
+// with no pluginName, the same requirement syncKongPlugin enforces at sync
+// time, surfaced at admission time instead.
+func (k *KongController) ValidateKongPlugin(raw []byte) error {
+	kp := &v1alpha1.KongPlugin{}
+	if err := json.Unmarshal(raw, kp); err != nil {
+		return fmt.Errorf("failed decoding kongplugin: %s", err)
+	}
+	if kp.Spec.PluginName == "" {
+		return fmt.Errorf("KongPlugin %s/%s is missing spec.pluginName", kp.Namespace, kp.Name)
+	}
+	return nil
+}
+
+// ValidateKongRoute implements admission.Validator by rejecting a KongRoute
+// whose Hosts contains an invalid hostname/SNI, the same check syncRoute
+// performs on Spec.Validate() at sync time, surfaced at admission time
+// instead.
+func (k *KongController) ValidateKongRoute(raw []byte) error {
+	route := &v1alpha1.KongRoute{}
+	if err := json.Unmarshal(raw, route); err != nil {
+		return fmt.Errorf("failed decoding kongroute: %s", err)
+	}
+	return route.Spec.Validate()
+}
+
+// ValidateIngress implements admission.Validator, rejecting an Ingress
+// whose rule backend references a Service the API server can't return, the
+// admission-time counterpart of the per-path check syncIngress performs
+// against its Service cache at sync time.
+func (k *KongController) ValidateIngress(ing *v1beta1.Ingress) error {
+	for _, r := range ing.Spec.Rules {
+		if r.HTTP == nil {
+			continue
+		}
+		for _, p := range r.HTTP.Paths {
+			if _, err := k.client.Core().Services(ing.Namespace).Get(p.Backend.ServiceName, metav1.GetOptions{}); err != nil {
+				return fmt.Errorf("backend service %s/%s not found: %s", ing.Namespace, p.Backend.ServiceName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateCABundle implements admission.CABundleUpdater by patching the
+// caBundle of every webhook entry in cfg.WebhookConfigName.
+func (k *KongController) UpdateCABundle(caBundle []byte) error {
+	webhookCfg, err := k.client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(k.cfg.WebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i := range webhookCfg.Webhooks {
+		if string(webhookCfg.Webhooks[i].ClientConfig.CABundle) != string(caBundle) {
+			webhookCfg.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	_, err = k.client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Update(webhookCfg)
+	return err
+}
+
+// leaderElectionIdentity returns this process's own identity for
+// leaderelection.LeaderElector, which determines whether the calling
+// process already holds the lease by comparing the lock's stored
+// HolderIdentity against this value. It must be unique per replica, unlike
+// e.g. cfg.WebhookConfigName which is the same on every replica.
+func leaderElectionIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		glog.Errorf("admission: failed reading hostname for leader election identity, falling back to pid: %s", err)
+		return fmt.Sprintf("pid-%d", os.Getpid())
+	}
+	return hostname
+}
+
+// webhookCABundleSource resolves the webhook's own serving certificate as
+// the caBundle callers need to trust it (the webhook serves a self-signed
+// leaf), reading from whichever backing store newWebhookCertWatcher used:
+// WebhookCertSecret takes precedence over WebhookCertPath, the same
+// precedence applied everywhere else the webhook cert is resolved.
+func webhookCABundleSource(k *KongController, cfg *Config) func() ([]byte, error) {
+	if cfg.WebhookCertSecret != "" {
+		return func() ([]byte, error) {
+			certPEM, _, err := k.fetchWebhookCertSecret()
+			return certPEM, err
+		}
+	}
+	return func() ([]byte, error) {
+		return ioutil.ReadFile(cfg.WebhookCertPath)
+	}
+}
+
+// newWebhookConfigReconciler builds the leader-elected caBundle reconciler,
+// or nil when either WebhookBindAddress or WebhookConfigName isn't set.
+func newWebhookConfigReconciler(k *KongController, cfg *Config) *admission.WebhookConfigReconciler {
+	if cfg.WebhookBindAddress == "" || cfg.WebhookConfigName == "" {
+		return nil
+	}
+	lock := &resourcelock.ConfigMapLock{
+		ConfigMapMeta: metav1.ObjectMeta{
+			Namespace: cfg.PodNamespace,
+			Name:      "kong-ingress-webhook-leader",
+		},
+		Client: k.client.Core(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: leaderElectionIdentity(),
+		},
+	}
+	return admission.NewWebhookConfigReconciler(webhookCABundleSource(k, cfg), k, lock, time.Minute)
+}
+
+// runWebhookServer starts the admission webhook's HTTPS listener, blocking
+// until stopc is closed. It's run in its own goroutine by Run, the same way
+// the admin pool and license agent are.
+func (k *KongController) runWebhookServer(stopc <-chan struct{}) {
+	server := admission.NewServer(k)
+	httpServer := &http.Server{
+		Addr:    k.cfg.WebhookBindAddress,
+		Handler: server.Handler(),
+		TLSConfig: &tls.Config{
+			GetCertificate: k.webhookCerts.GetCertificate,
+		},
+	}
+	go func() {
+		<-stopc
+		httpServer.Close()
+	}()
+	glog.Infof("admission: serving validating webhook on %s", k.cfg.WebhookBindAddress)
+	if err := httpServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+		glog.Errorf("admission: webhook server exited: %s", err)
+	}
+}
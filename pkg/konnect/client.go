@@ -0,0 +1,57 @@
+// Package konnect mirrors locally-applied Kong configuration to Kong
+// Konnect's config-upload endpoint, for operators who run their own Kong
+// data plane but still want Konnect's fleet-wide observability and config
+// backup.
+package konnect
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	restclient "k8s.io/client-go/rest"
+)
+
+// Client uploads declarative configuration snapshots to a single Konnect
+// Runtime Group over mTLS.
+type Client struct {
+	address        string
+	runtimeGroupID string
+	httpClient     *http.Client
+}
+
+// NewClient builds a Client authenticated with the client certificate/key
+// pair Konnect issued for runtimeGroupID.
+func NewClient(address, runtimeGroupID string, tlsConfig restclient.TLSClientConfig) (*Client, error) {
+	cert, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("konnect: failed loading client certificate: %s", err)
+	}
+	return &Client{
+		address:        address,
+		runtimeGroupID: runtimeGroupID,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+			},
+		},
+	}, nil
+}
+
+// SyncConfig uploads a declarative configuration snapshot for the client's
+// runtime group. SyncConfig makes a single attempt; callers that want
+// retries (e.g. the controller's konnect mirror) are expected to call it
+// again on error.
+func (c *Client) SyncConfig(config []byte) error {
+	url := fmt.Sprintf("%s/v2/runtime-groups/%s/config", c.address, c.runtimeGroupID)
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(config))
+	if err != nil {
+		return fmt.Errorf("konnect: failed uploading config: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("konnect: config upload rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Package licenseagent periodically pulls an Enterprise license from an
+// upstream source (a URL, or a Kubernetes Secret) and injects it into every
+// configured Kong Admin API instance, caching the last-known-good payload so
+// a transient upstream outage doesn't strip a currently-licensed cluster.
+package licenseagent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Status reflects the agent's last known license state, surfaced through
+// the controller's health endpoints so ops can tell at a glance when a
+// cluster is running unlicensed.
+type Status struct {
+	Licensed      bool
+	LastSource    string
+	LastFetchedAt time.Time
+	LastError     string
+}
+
+// Injector pushes a license payload into every configured Kong Admin API
+// instance. Kept as an interface so licenseagent doesn't need to import
+// pkg/controller or pkg/kong.
+type Injector interface {
+	InjectLicense(payload []byte) error
+}
+
+// Agent periodically pulls a license and hands it to an Injector, caching
+// the last payload it successfully fetched so a transient upstream outage
+// degrades to last-known-good instead of removing the license entirely.
+type Agent struct {
+	source      string // upstream URL; unused when fetchSecret is set
+	fetchSecret func() ([]byte, error)
+	injector    Injector
+
+	mu      sync.RWMutex
+	license []byte
+	status  Status
+}
+
+// NewAgent builds an Agent. fetchSecret is nil when the license is fetched
+// from source directly; non-nil implementations read a Kubernetes Secret
+// instead, in which case source is ignored.
+func NewAgent(source string, fetchSecret func() ([]byte, error), injector Injector) *Agent {
+	return &Agent{source: source, fetchSecret: fetchSecret, injector: injector}
+}
+
+// Status returns a snapshot of the agent's last known license state.
+func (a *Agent) Status() Status {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.status
+}
+
+// Run fetches a license immediately and then every interval, until stopc is
+// closed. Callers that can also detect license changes out-of-band (e.g. a
+// Secret watch) should call Refresh directly on those events in addition to
+// running Run for the periodic fallback poll.
+func (a *Agent) Run(interval time.Duration, stopc <-chan struct{}) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	a.Refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.Refresh()
+		case <-stopc:
+			return
+		}
+	}
+}
+
+// Refresh fetches a license and pushes it to the injector immediately. On
+// fetch failure it degrades to the last cached payload (if any) rather than
+// injecting nothing, so a transient upstream outage doesn't strip a license
+// from an already-licensed cluster.
+func (a *Agent) Refresh() {
+	payload, source, err := a.fetch()
+	a.mu.Lock()
+	if err != nil {
+		a.status.LastError = err.Error()
+		glog.Warningf("licenseagent: failed refreshing license from %s, degrading to last-known-good: %s", source, err)
+		payload = a.license
+	} else {
+		a.license = payload
+		a.status.LastError = ""
+		a.status.LastSource = source
+		a.status.LastFetchedAt = time.Now()
+	}
+	a.status.Licensed = len(payload) > 0
+	a.mu.Unlock()
+
+	if len(payload) == 0 {
+		return
+	}
+	if err := a.injector.InjectLicense(payload); err != nil {
+		glog.Errorf("licenseagent: failed injecting license: %s", err)
+	}
+}
+
+func (a *Agent) fetch() ([]byte, string, error) {
+	if a.fetchSecret != nil {
+		payload, err := a.fetchSecret()
+		return payload, "secret", err
+	}
+	if a.source == "" {
+		return nil, "", fmt.Errorf("licenseagent: neither a license source nor a license secret is configured")
+	}
+	resp, err := http.Get(a.source)
+	if err != nil {
+		return nil, a.source, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, a.source, fmt.Errorf("unexpected status %d fetching license", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, a.source, err
+}
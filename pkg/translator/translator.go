@@ -0,0 +1,103 @@
+// Package translator decouples "what Kong configuration does this
+// Kubernetes state imply" from "how is that configuration actually pushed
+// to Kong", so the two can vary independently and third parties can depend
+// on the former without pulling in the whole controller. A Backend applies
+// a TargetConfig and reports what's currently active; the controller wires
+// one of the two backends below based on Config.SyncBackend.
+package translator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// TargetConfig is the desired state for a single reconciliation pass.
+// Each Backend reads only its own field: EntityBackend runs EntityApply and
+// ignores Declarative, DeclarativeBackend reloads Declarative and ignores
+// EntityApply.
+type TargetConfig struct {
+	// Declarative is a full desired Kong config snapshot, used by
+	// DeclarativeBackend to diff-and-reload in a single /config write.
+	Declarative []byte
+	// EntityApply performs one reconciliation pass' worth of one-write-per-
+	// entity admin API calls (API, plugins, certificates, ...), used by
+	// EntityBackend. It's a closure rather than pre-rendered data because
+	// the entity path looks up existing objects (for their UIDs) live
+	// against each admin instance as it writes, instead of computing
+	// everything up front.
+	EntityApply func(ctx context.Context) error
+}
+
+// Backend applies a TargetConfig to Kong and reports the configuration
+// currently active there.
+type Backend interface {
+	// Sync pushes target to Kong.
+	Sync(ctx context.Context, target TargetConfig) error
+	// Fetch returns Kong's currently active configuration. Backends with no
+	// single fetchable snapshot (EntityBackend) return nil, nil.
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// EntityBackend implements Backend by running TargetConfig.EntityApply
+// directly: the controller's original sync strategy, issuing one admin API
+// write per Kong entity instead of reloading a whole-cluster snapshot.
+type EntityBackend struct{}
+
+// NewEntityBackend builds an EntityBackend.
+func NewEntityBackend() *EntityBackend {
+	return &EntityBackend{}
+}
+
+// Sync runs target.EntityApply.
+func (b *EntityBackend) Sync(ctx context.Context, target TargetConfig) error {
+	if target.EntityApply == nil {
+		return fmt.Errorf("translator: entity backend requires TargetConfig.EntityApply")
+	}
+	return target.EntityApply(ctx)
+}
+
+// Fetch always returns nil, nil: the entity backend has no single
+// fetchable snapshot to diff against.
+func (b *EntityBackend) Fetch(ctx context.Context) ([]byte, error) {
+	return nil, nil
+}
+
+// DeclarativeBackend implements Backend "deck"-style: it takes the full
+// desired state rendered into TargetConfig.Declarative and only pushes a
+// single reload when it differs from a freshly fetched snapshot, instead of
+// writing one entity at a time.
+type DeclarativeBackend struct {
+	fetch func(ctx context.Context) ([]byte, error)
+	apply func(ctx context.Context, target []byte) error
+}
+
+// NewDeclarativeBackend builds a DeclarativeBackend. fetch retrieves Kong's
+// currently active config snapshot; apply pushes a full config reload.
+func NewDeclarativeBackend(fetch func(ctx context.Context) ([]byte, error), apply func(ctx context.Context, target []byte) error) *DeclarativeBackend {
+	return &DeclarativeBackend{fetch: fetch, apply: apply}
+}
+
+// Fetch returns Kong's currently active config snapshot.
+func (b *DeclarativeBackend) Fetch(ctx context.Context) ([]byte, error) {
+	return b.fetch(ctx)
+}
+
+// Sync fetches the currently active config and only reloads when
+// target.Declarative differs from it, so an unchanged Ingress set doesn't
+// cause a config reload (and the brief proxy-side cache invalidation that
+// comes with one) on every resync. A target with no Declarative snapshot
+// (e.g. one carrying only EntityApply) is a no-op.
+func (b *DeclarativeBackend) Sync(ctx context.Context, target TargetConfig) error {
+	if target.Declarative == nil {
+		return nil
+	}
+	current, err := b.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("translator: failed fetching current config: %s", err)
+	}
+	if bytes.Equal(current, target.Declarative) {
+		return nil
+	}
+	return b.apply(ctx, target.Declarative)
+}